@@ -3,6 +3,7 @@ package main
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/cristiandonosoc/gunreal/cmd/gunreal/project"
 
@@ -31,5 +32,8 @@ func init() {
 }
 
 func main() {
-	rootCmd.Execute()
+	// Cobra already printed the error.
+	if err := rootCmd.Execute(); err != nil {
+		os.Exit(1)
+	}
 }
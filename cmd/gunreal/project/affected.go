@@ -0,0 +1,109 @@
+package project
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cristiandonosoc/gunreal/pkg/unreal"
+	"github.com/spf13/cobra"
+)
+
+var (
+	gAffectedFlags = struct {
+		since string
+		json  bool
+	}{}
+
+	affectedCmd = &cobra.Command{
+		Use:   "affected",
+		Short: "Lists the modules, plugins and targets affected by a set of changed files",
+		Long: `Lists the modules, plugins and targets affected by a set of changed files.
+
+The changed files are either the ones changed since the git ref given by --since, or a list of
+paths (one per line) read from stdin. Relative paths are relative to the current directory.`,
+		Args:         cobra.NoArgs,
+		RunE:         executeAffected,
+		SilenceUsage: true,
+	}
+)
+
+func init() {
+	ProjectSectionCmd.AddCommand(affectedCmd)
+
+	affectedCmd.Flags().StringVar(&gAffectedFlags.since, "since", "",
+		"Git ref to compare against. If empty, the changed files are read from stdin")
+	affectedCmd.Flags().BoolVar(&gAffectedFlags.json, "json", false, "Output as JSON")
+}
+
+func executeAffected(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	project, err := unreal.NewProject(gGunrealConfig)
+	if err != nil {
+		return fmt.Errorf("reading project: %w", err)
+	}
+
+	if err := project.IndexModules(ctx); err != nil {
+		return fmt.Errorf("indexing unreal project: %w", err)
+	}
+
+	var paths []string
+	if gAffectedFlags.since != "" {
+		paths, err = project.ChangedFilesSince(gAffectedFlags.since)
+		if err != nil {
+			return fmt.Errorf("finding changed files: %w", err)
+		}
+	} else {
+		paths, err = readPathsFromStdin()
+		if err != nil {
+			return err
+		}
+	}
+
+	affected, err := project.AffectedBy(ctx, paths)
+	if err != nil {
+		return fmt.Errorf("calculating affected modules: %w", err)
+	}
+
+	if gAffectedFlags.json {
+		return printJSON(affected)
+	}
+
+	fmt.Printf("Changed files: %d\n", len(paths))
+	printFileList("CHANGED MODULES", affected.ChangedModules)
+	printFileList("AFFECTED MODULES", affected.Modules)
+	printFileList("AFFECTED PLUGINS", affected.Plugins)
+	printFileList("AFFECTED TARGETS", affected.Targets)
+	printFileList("UNATTRIBUTED FILES", affected.Unattributed)
+
+	return nil
+}
+
+// readPathsFromStdin reads one path per line from stdin and makes them absolute.
+func readPathsFromStdin() ([]string, error) {
+	var paths []string
+
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		path, err := filepath.Abs(line)
+		if err != nil {
+			return nil, fmt.Errorf("making %q abs: %w", line, err)
+		}
+		paths = append(paths, path)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading stdin: %w", err)
+	}
+
+	return paths, nil
+}
@@ -0,0 +1,70 @@
+package project
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cristiandonosoc/gunreal/pkg/unreal"
+	"github.com/spf13/cobra"
+)
+
+var (
+	gCheckDepsFlags = struct {
+		json bool
+	}{}
+
+	checkDepsCmd = &cobra.Command{
+		Use:          "check-deps",
+		Short:        "Checks the #include directives of each module against its .Build.cs dependencies",
+		Args:         cobra.NoArgs,
+		RunE:         executeCheckDeps,
+		SilenceUsage: true,
+	}
+)
+
+func init() {
+	ProjectSectionCmd.AddCommand(checkDepsCmd)
+
+	checkDepsCmd.Flags().BoolVar(&gCheckDepsFlags.json, "json", false, "Output as JSON")
+}
+
+func executeCheckDeps(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	project, err := loadIncludesProject(ctx)
+	if err != nil {
+		return err
+	}
+
+	diagnostics, err := project.CheckModuleDependencies(ctx)
+	if err != nil {
+		return fmt.Errorf("checking module dependencies: %w", err)
+	}
+
+	return reportDiagnostics(diagnostics, gCheckDepsFlags.json)
+}
+
+// reportDiagnostics prints the diagnostics and returns an error if any of them is an error.
+func reportDiagnostics(diagnostics []*unreal.Diagnostic, asJSON bool) error {
+	if asJSON {
+		if err := printJSON(diagnostics); err != nil {
+			return err
+		}
+	} else {
+		for _, diagnostic := range diagnostics {
+			fmt.Println(diagnostic)
+		}
+	}
+
+	errors := unreal.CountDiagnostics(diagnostics, unreal.DiagnosticSeverity_Error)
+	warnings := unreal.CountDiagnostics(diagnostics, unreal.DiagnosticSeverity_Warning)
+	if !asJSON {
+		fmt.Printf("\n%d errors, %d warnings\n", errors, warnings)
+	}
+
+	if errors > 0 {
+		return fmt.Errorf("found %d errors", errors)
+	}
+
+	return nil
+}
@@ -1,6 +1,7 @@
 package project
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/cristiandonosoc/gunreal/pkg/unreal"
@@ -8,27 +9,115 @@ import (
 )
 
 var (
+	gCompdbFlags = struct {
+		style   string
+		force   bool
+		backup  bool
+		workers int
+	}{}
+
 	compdbCmd = &cobra.Command{
-		Use: "compdb",
-		Short: "Generates an usable Compilation Dabatase",
-		RunE: executeCompdb,
+		Use:          "compdb",
+		Short:        "Generates an usable Compilation Dabatase",
+		RunE:         executeCompdb,
 		SilenceUsage: true,
 	}
 )
 
 func init() {
 	ProjectSectionCmd.AddCommand(compdbCmd)
+
+	compdbCmd.Flags().StringVar(&gCompdbFlags.style, "style", "msvc",
+		"Syntax of the compdb arguments: msvc (cl.exe) or gnu (clang driver)")
+	compdbCmd.Flags().BoolVar(&gCompdbFlags.force, "force", false,
+		"Always re-run UBT, even if no build file changed since the last generation")
+	compdbCmd.Flags().BoolVar(&gCompdbFlags.backup, "backup", false,
+		"Keep the previous compile_commands.json as compile_commands.json.bak")
+	compdbCmd.Flags().IntVar(&gCompdbFlags.workers, "workers", 0,
+		"How many entries to rewrite concurrently (0 uses the default)")
 }
 
 func executeCompdb(cmd *cobra.Command, args []string) error {
+	style, err := unreal.NewCompDBStyle(gCompdbFlags.style)
+	if err != nil {
+		return fmt.Errorf("parsing style: %w", err)
+	}
+
 	project, err := unreal.NewProject(gGunrealConfig)
 	if err != nil {
 		return fmt.Errorf("reading project: %w", err)
 	}
 
-	if err := project.GenerateCompDB(); err != nil {
+	options := &unreal.CompDBOptions{
+		Style:   style,
+		Force:   gCompdbFlags.force,
+		Backup:  gCompdbFlags.backup,
+		Workers: gCompdbFlags.workers,
+	}
+	if err := project.GenerateCompDB(context.Background(), options); err != nil {
 		return fmt.Errorf("generating compdb: %w", err)
 	}
 
 	return nil
 }
+
+var (
+	compdbCheckCmd = &cobra.Command{
+		Use:          "check",
+		Short:        "Validates the existing compilation database against the project",
+		Args:         cobra.NoArgs,
+		RunE:         executeCompdbCheck,
+		SilenceUsage: true,
+	}
+
+	compdbDiffCmd = &cobra.Command{
+		Use:          "diff <old> <new>",
+		Short:        "Explains what changed between two compilation databases",
+		Args:         cobra.ExactArgs(2),
+		RunE:         executeCompdbDiff,
+		SilenceUsage: true,
+	}
+)
+
+func init() {
+	compdbCmd.AddCommand(compdbCheckCmd)
+	compdbCmd.AddCommand(compdbDiffCmd)
+}
+
+func executeCompdbCheck(cmd *cobra.Command, args []string) error {
+	project, err := unreal.NewProject(gGunrealConfig)
+	if err != nil {
+		return fmt.Errorf("reading project: %w", err)
+	}
+
+	issues, err := project.CheckCompDB(context.Background())
+	if err != nil {
+		return fmt.Errorf("checking compdb: %w", err)
+	}
+
+	for _, issue := range issues {
+		fmt.Println(issue)
+	}
+
+	if len(issues) > 0 {
+		return fmt.Errorf("found %d issues in %q", len(issues), project.CompDBPath())
+	}
+
+	fmt.Printf("No issues found in %q\n", project.CompDBPath())
+	return nil
+}
+
+func executeCompdbDiff(cmd *cobra.Command, args []string) error {
+	diff, err := unreal.DiffCompDBs(args[0], args[1])
+	if err != nil {
+		return fmt.Errorf("diffing compdbs: %w", err)
+	}
+
+	if diff.IsEmpty() {
+		fmt.Println("Compilation databases are equivalent.")
+		return nil
+	}
+
+	fmt.Print(diff.Describe())
+	return nil
+}
@@ -0,0 +1,93 @@
+package project
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/cristiandonosoc/gunreal/pkg/unreal"
+	"github.com/spf13/cobra"
+)
+
+var (
+	gCrashesFlags = struct {
+		dir  string
+		json bool
+	}{}
+
+	crashesCmd = &cobra.Command{
+		Use:   "crashes",
+		Short: "Summarizes the crash reports in Saved/Crashes, grouped by callstack",
+		Long: `Reads the CrashContext.runtime-xml of every crash folder and groups the crashes by the signature
+of their callstack, so that the same crash reported many times shows up once.`,
+		Args:         cobra.NoArgs,
+		RunE:         executeCrashes,
+		SilenceUsage: true,
+	}
+)
+
+func init() {
+	ProjectSectionCmd.AddCommand(crashesCmd)
+
+	crashesCmd.Flags().StringVar(&gCrashesFlags.dir, "dir", "",
+		"Directory with crash folders to read instead of <ProjectDir>/Saved/Crashes (eg. crashes from QA)")
+	crashesCmd.Flags().BoolVar(&gCrashesFlags.json, "json", false, "Output as JSON")
+}
+
+func executeCrashes(cmd *cobra.Command, args []string) error {
+	project, err := unreal.NewProject(gGunrealConfig)
+	if err != nil {
+		return fmt.Errorf("reading project: %w", err)
+	}
+
+	crashes, warnings, err := project.Crashes(gCrashesFlags.dir)
+	if err != nil {
+		return fmt.Errorf("loading crashes: %w", err)
+	}
+
+	// Warnings go to stderr, so that they do not break the JSON output.
+	for _, warning := range warnings {
+		fmt.Fprintln(os.Stderr, warning)
+	}
+
+	groups := unreal.GroupCrashes(crashes)
+
+	if gCrashesFlags.json {
+		if groups == nil {
+			groups = []*unreal.CrashGroup{}
+		}
+		return printJSON(groups)
+	}
+
+	fmt.Printf("CRASHES: %d (%d distinct)\n", len(crashes), len(groups))
+	for _, group := range groups {
+		fmt.Println()
+		fmt.Printf("[%dx] %s\n", len(group.Crashes), firstLine(group.ErrorMessage))
+		fmt.Printf("- SIGNATURE: %s\n", group.Signature)
+		fmt.Printf("- TOP FRAME: %s\n", group.TopFrame)
+		fmt.Printf("- SEEN: %s - %s\n", group.FirstSeen.Local().Format(time.DateTime), group.LastSeen.Local().Format(time.DateTime))
+
+		latest := group.Crashes[len(group.Crashes)-1]
+		fmt.Printf("- ENGINE: %s (%s, %s)\n", latest.EngineVersion, latest.BuildConfiguration, latest.Platform)
+
+		fmt.Println("- CRASHES:")
+		for _, crash := range group.Crashes {
+			fmt.Printf("  - %s\n", crash.Dir)
+			if crash.LogFile != "" {
+				fmt.Printf("    LOG: %s\n", crash.LogFile)
+			}
+		}
+	}
+
+	return nil
+}
+
+// firstLine returns the first line of |s|, as crash messages can be very long.
+func firstLine(s string) string {
+	for i, c := range s {
+		if c == '\n' || c == '\r' {
+			return s[:i]
+		}
+	}
+	return s
+}
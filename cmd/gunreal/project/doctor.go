@@ -0,0 +1,49 @@
+package project
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cristiandonosoc/gunreal/pkg/unreal"
+	"github.com/spf13/cobra"
+)
+
+var (
+	gDoctorFlags = struct {
+		json bool
+	}{}
+
+	doctorCmd = &cobra.Command{
+		Use:          "doctor",
+		Short:        "Checks the .uproject and .uplugin descriptors against the indexed source",
+		Args:         cobra.NoArgs,
+		RunE:         executeDoctor,
+		SilenceUsage: true,
+	}
+)
+
+func init() {
+	ProjectSectionCmd.AddCommand(doctorCmd)
+
+	doctorCmd.Flags().BoolVar(&gDoctorFlags.json, "json", false, "Output as JSON")
+}
+
+func executeDoctor(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	project, err := unreal.NewProject(gGunrealConfig)
+	if err != nil {
+		return fmt.Errorf("reading project: %w", err)
+	}
+
+	if err := indexEngineIfRequested(ctx, project); err != nil {
+		return err
+	}
+
+	diagnostics, err := project.Doctor(ctx)
+	if err != nil {
+		return fmt.Errorf("checking project: %w", err)
+	}
+
+	return reportDiagnostics(diagnostics, gDoctorFlags.json)
+}
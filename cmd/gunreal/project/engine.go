@@ -0,0 +1,61 @@
+package project
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cristiandonosoc/gunreal/pkg/unreal"
+	"github.com/spf13/cobra"
+)
+
+var (
+	gIndexEngineFlags = struct {
+		reload bool
+	}{}
+
+	indexEngineCmd = &cobra.Command{
+		Use:          "index-engine",
+		Short:        "Indexes the engine modules and plugins and caches the result",
+		Args:         cobra.NoArgs,
+		RunE:         executeIndexEngine,
+		SilenceUsage: true,
+	}
+)
+
+func init() {
+	ProjectSectionCmd.AddCommand(indexEngineCmd)
+
+	indexEngineCmd.Flags().BoolVar(&gIndexEngineFlags.reload, "reload", false,
+		"Ignore the cached index and scan the engine again")
+}
+
+func executeIndexEngine(cmd *cobra.Command, args []string) error {
+	project, err := unreal.NewProject(gGunrealConfig)
+	if err != nil {
+		return fmt.Errorf("reading project: %w", err)
+	}
+
+	if err := project.IndexEngine(context.Background(), gIndexEngineFlags.reload); err != nil {
+		return fmt.Errorf("indexing engine: %w", err)
+	}
+
+	fmt.Printf("ENGINE: %s\n", project.Engine.Dir)
+	fmt.Printf("- KEY: %s\n", project.Engine.Key)
+	fmt.Printf("- MODULES: %d\n", len(project.Engine.Modules))
+	fmt.Printf("- PLUGINS: %d\n", len(project.Engine.Plugins))
+
+	return nil
+}
+
+// indexEngineIfRequested indexes the engine when the --engine flag is set.
+func indexEngineIfRequested(ctx context.Context, project *unreal.Project) error {
+	if !gFlags.engine {
+		return nil
+	}
+
+	if err := project.IndexEngine(ctx, false); err != nil {
+		return fmt.Errorf("indexing engine: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package project
+
+import (
+	"fmt"
+
+	"github.com/cristiandonosoc/gunreal/pkg/unreal"
+	"github.com/spf13/cobra"
+)
+
+var (
+	engineAssociationCmd = &cobra.Command{
+		Use:          "engine-association [value]",
+		Short:        "Prints the EngineAssociation of the .uproject, or sets it if a value is given",
+		Args:         cobra.MaximumNArgs(1),
+		RunE:         executeEngineAssociation,
+		SilenceUsage: true,
+	}
+)
+
+func init() {
+	ProjectSectionCmd.AddCommand(engineAssociationCmd)
+}
+
+func executeEngineAssociation(cmd *cobra.Command, args []string) error {
+	project, err := unreal.NewProject(gGunrealConfig)
+	if err != nil {
+		return fmt.Errorf("reading project: %w", err)
+	}
+
+	if len(args) == 0 {
+		if project.LoadedUProject == nil {
+			return fmt.Errorf("no uproject loaded")
+		}
+		fmt.Println(project.LoadedUProject.EngineAssociation)
+		return nil
+	}
+
+	if err := project.SetEngineAssociation(args[0]); err != nil {
+		return fmt.Errorf("setting engine association: %w", err)
+	}
+
+	return nil
+}
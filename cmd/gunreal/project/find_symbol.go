@@ -0,0 +1,86 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/cristiandonosoc/gunreal/pkg/unreal"
+	"github.com/spf13/cobra"
+)
+
+var (
+	gFindSymbolFlags = struct {
+		kinds  []string
+		module string
+		json   bool
+	}{}
+
+	findSymbolCmd = &cobra.Command{
+		Use:          "find-symbol <regex>",
+		Short:        "Searches class, struct, enum, function and macro definitions across the indexed modules",
+		Args:         cobra.ExactArgs(1),
+		RunE:         executeFindSymbol,
+		SilenceUsage: true,
+	}
+)
+
+func init() {
+	ProjectSectionCmd.AddCommand(findSymbolCmd)
+
+	findSymbolCmd.Flags().StringSliceVar(&gFindSymbolFlags.kinds, "kind", nil,
+		"Only show symbols of these kinds (class, struct, enum, function, macro)")
+	findSymbolCmd.Flags().StringVar(&gFindSymbolFlags.module, "module", "",
+		"Only show symbols defined in this module")
+	findSymbolCmd.Flags().BoolVar(&gFindSymbolFlags.json, "json", false, "Output as JSON")
+}
+
+func executeFindSymbol(cmd *cobra.Command, args []string) error {
+	pattern, err := regexp.Compile(args[0])
+	if err != nil {
+		return fmt.Errorf("compiling regex %q: %w", args[0], err)
+	}
+
+	var kinds []unreal.SymbolKind
+	for _, id := range gFindSymbolFlags.kinds {
+		kind, err := unreal.NewSymbolKind(id)
+		if err != nil {
+			return fmt.Errorf("parsing kind: %w", err)
+		}
+		kinds = append(kinds, kind)
+	}
+
+	ctx := context.Background()
+
+	project, err := unreal.NewProject(gGunrealConfig)
+	if err != nil {
+		return fmt.Errorf("reading project: %w", err)
+	}
+
+	if err := project.IndexModules(ctx); err != nil {
+		return fmt.Errorf("indexing unreal project: %w", err)
+	}
+
+	symbols, err := project.FindSymbols(ctx, pattern, kinds)
+	if err != nil {
+		return fmt.Errorf("finding symbols: %w", err)
+	}
+
+	var filtered []*unreal.Symbol
+	for _, symbol := range symbols {
+		if gFindSymbolFlags.module != "" && symbol.Module.Name != gFindSymbolFlags.module {
+			continue
+		}
+		filtered = append(filtered, symbol)
+	}
+
+	if gFindSymbolFlags.json {
+		return printJSON(filtered)
+	}
+
+	for _, symbol := range filtered {
+		fmt.Printf("%-8s %s [module:%s] %s:%d\n", symbol.Kind, symbol.Name, symbol.Module.Name, symbol.File, symbol.Line)
+	}
+
+	return nil
+}
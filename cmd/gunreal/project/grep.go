@@ -0,0 +1,99 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/cristiandonosoc/gunreal/pkg/unreal"
+	"github.com/spf13/cobra"
+)
+
+var (
+	gGrepFlags = struct {
+		modules    []string
+		plugins    []string
+		includeUHT bool
+		platform   string
+		ignoreCase bool
+		json       bool
+	}{}
+
+	grepCmd = &cobra.Command{
+		Use:          "grep <pattern>",
+		Short:        "Searches a regex over the files of the indexed modules",
+		Args:         cobra.ExactArgs(1),
+		RunE:         executeGrep,
+		SilenceUsage: true,
+	}
+)
+
+func init() {
+	ProjectSectionCmd.AddCommand(grepCmd)
+
+	grepCmd.Flags().StringSliceVar(&gGrepFlags.modules, "module", nil, "Only search within these modules")
+	grepCmd.Flags().StringSliceVar(&gGrepFlags.plugins, "plugin", nil, "Only search within the modules of these plugins")
+	grepCmd.Flags().BoolVar(&gGrepFlags.includeUHT, "include-uht", false, "Also search the UHT generated files")
+	grepCmd.Flags().StringVar(&gGrepFlags.platform, "platform", "win64", "Platform of the UHT generated files")
+	grepCmd.Flags().BoolVarP(&gGrepFlags.ignoreCase, "ignore-case", "i", false, "Case insensitive search")
+	grepCmd.Flags().BoolVar(&gGrepFlags.json, "json", false, "Output as JSON")
+}
+
+func executeGrep(cmd *cobra.Command, args []string) error {
+	expr := args[0]
+	if gGrepFlags.ignoreCase {
+		expr = "(?i)" + expr
+	}
+
+	pattern, err := regexp.Compile(expr)
+	if err != nil {
+		return fmt.Errorf("compiling regex %q: %w", args[0], err)
+	}
+
+	platform, err := unreal.NewUnrealPlatform(gGrepFlags.platform)
+	if err != nil {
+		return fmt.Errorf("parsing platform: %w", err)
+	}
+
+	ctx := context.Background()
+
+	project, err := unreal.NewProject(gGunrealConfig)
+	if err != nil {
+		return fmt.Errorf("reading project: %w", err)
+	}
+
+	if err := project.IndexModules(ctx); err != nil {
+		return fmt.Errorf("indexing unreal project: %w", err)
+	}
+
+	options := &unreal.GrepOptions{
+		Modules:    gGrepFlags.modules,
+		Plugins:    gGrepFlags.plugins,
+		IncludeUHT: gGrepFlags.includeUHT,
+		Platform:   platform,
+	}
+	matches, err := project.Grep(ctx, pattern, options)
+	if err != nil {
+		return fmt.Errorf("searching: %w", err)
+	}
+
+	if gGrepFlags.json {
+		return printJSON(matches)
+	}
+
+	// Matches come sorted by module, so we can group them as we go.
+	var currentModule *unreal.Module
+	for _, match := range matches {
+		if match.Module != currentModule {
+			if currentModule != nil {
+				fmt.Println()
+			}
+			fmt.Printf("== %s ==\n", match.Module.Name)
+			currentModule = match.Module
+		}
+
+		fmt.Println(match)
+	}
+
+	return nil
+}
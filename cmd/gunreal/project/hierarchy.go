@@ -0,0 +1,92 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cristiandonosoc/gunreal/pkg/unreal"
+	"github.com/spf13/cobra"
+)
+
+var (
+	gHierarchyFlags = struct {
+		format string
+	}{}
+
+	hierarchyCmd = &cobra.Command{
+		Use:          "hierarchy <class>",
+		Short:        "Prints the ancestors and descendants of a class across the project",
+		Args:         cobra.ExactArgs(1),
+		RunE:         executeHierarchy,
+		SilenceUsage: true,
+	}
+)
+
+func init() {
+	ProjectSectionCmd.AddCommand(hierarchyCmd)
+
+	hierarchyCmd.Flags().StringVar(&gHierarchyFlags.format, "format", "tree",
+		"Output format: tree, flat or json")
+}
+
+func executeHierarchy(cmd *cobra.Command, args []string) error {
+	switch gHierarchyFlags.format {
+	case "tree", "flat", "json":
+	default:
+		return fmt.Errorf("unrecognized format %q", gHierarchyFlags.format)
+	}
+
+	project, err := loadReflectedProject(context.Background())
+	if err != nil {
+		return err
+	}
+
+	hierarchy, err := project.ClassHierarchy(args[0])
+	if err != nil {
+		return fmt.Errorf("calculating hierarchy: %w", err)
+	}
+
+	switch gHierarchyFlags.format {
+	case "json":
+		return printJSON(hierarchy)
+	case "flat":
+		for _, ancestor := range hierarchy.Ancestors {
+			fmt.Printf("ancestor   %s\n", describeClassNode(ancestor))
+		}
+		fmt.Printf("class      %s\n", describeClassNode(hierarchy.Class))
+		for _, descendant := range hierarchy.Descendants() {
+			fmt.Printf("descendant %s\n", describeClassNode(descendant))
+		}
+	case "tree":
+		// Ancestors are stored from the parent upwards, but a tree reads from the top.
+		depth := 0
+		for i := len(hierarchy.Ancestors) - 1; i >= 0; i-- {
+			fmt.Printf("%s%s\n", strings.Repeat("  ", depth), describeClassNode(hierarchy.Ancestors[i]))
+			depth++
+		}
+		printClassTree(hierarchy.Class, depth, true)
+	}
+
+	return nil
+}
+
+func printClassTree(node *unreal.ClassNode, depth int, queried bool) {
+	marker := ""
+	if queried {
+		marker = "* "
+	}
+	fmt.Printf("%s%s%s\n", strings.Repeat("  ", depth), marker, describeClassNode(node))
+
+	for _, child := range node.Children {
+		printClassTree(child, depth+1, false)
+	}
+}
+
+func describeClassNode(node *unreal.ClassNode) string {
+	if node.File == nil || node.File.Module == nil {
+		return fmt.Sprintf("%s (external)", node.Name)
+	}
+
+	return fmt.Sprintf("%s [module:%s] %s:%d", node.Name, node.File.Module.Name, node.File.ModulePath(), node.Type.Line)
+}
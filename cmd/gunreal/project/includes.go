@@ -0,0 +1,121 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/cristiandonosoc/gunreal/pkg/unreal"
+	"github.com/spf13/cobra"
+)
+
+var (
+	gIncludesFlags = struct {
+		json bool
+	}{}
+
+	includesCmd = &cobra.Command{
+		Use:          "includes <file>",
+		Short:        "Shows the include graph around a file: includes, includers and unresolved includes",
+		Args:         cobra.ExactArgs(1),
+		RunE:         executeIncludes,
+		SilenceUsage: true,
+	}
+)
+
+func init() {
+	ProjectSectionCmd.AddCommand(includesCmd)
+
+	includesCmd.Flags().BoolVar(&gIncludesFlags.json, "json", false, "Output as JSON")
+}
+
+func executeIncludes(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	project, err := loadIncludesProject(ctx)
+	if err != nil {
+		return err
+	}
+	graph := project.IncludeGraph()
+
+	path := args[0]
+	if abs, err := filepath.Abs(path); err == nil {
+		if _, ok := graph.Includes[abs]; ok {
+			path = abs
+		}
+	}
+
+	file, err := graph.FindFile(path)
+	if err != nil {
+		return err
+	}
+
+	result := struct {
+		File                string                     `json:"file"`
+		DirectIncludes      []string                   `json:"direct_includes"`
+		TransitiveIncludes  []string                   `json:"transitive_includes"`
+		Includers           []string                   `json:"includers"`
+		TransitiveIncluders []string                   `json:"transitive_includers"`
+		Unresolved          []*unreal.IncludeDirective `json:"unresolved"`
+		OutsideIncludePaths []*unreal.IncludeDirective `json:"outside_include_paths"`
+	}{
+		File:                file,
+		DirectIncludes:      graph.DirectIncludes(file),
+		TransitiveIncludes:  graph.TransitiveIncludes(file),
+		Includers:           graph.Includers(file),
+		TransitiveIncluders: graph.TransitiveIncluders(file),
+		Unresolved:          graph.Unresolved(file),
+		OutsideIncludePaths: graph.OutsideIncludePaths(file),
+	}
+
+	if gIncludesFlags.json {
+		return printJSON(result)
+	}
+
+	fmt.Printf("FILE: %s\n", result.File)
+	printFileList("DIRECT INCLUDES", result.DirectIncludes)
+	printFileList("TRANSITIVE INCLUDES", result.TransitiveIncludes)
+	printFileList("INCLUDERS", result.Includers)
+	printFileList("TRANSITIVE INCLUDERS", result.TransitiveIncluders)
+
+	fmt.Printf("\nUNRESOLVED INCLUDES: %d\n", len(result.Unresolved))
+	for _, include := range result.Unresolved {
+		fmt.Printf("- %s (line %d)\n", include.Path, include.Line)
+	}
+
+	fmt.Printf("\nINCLUDES OUTSIDE THE MODULE INCLUDE PATHS: %d\n", len(result.OutsideIncludePaths))
+	for _, include := range result.OutsideIncludePaths {
+		fmt.Printf("- %s (line %d) -> %s\n", include.Path, include.Line, include.Resolved)
+	}
+
+	return nil
+}
+
+func printFileList(title string, files []string) {
+	fmt.Printf("\n%s: %d\n", title, len(files))
+	for _, file := range files {
+		fmt.Printf("- %s\n", file)
+	}
+}
+
+// loadIncludesProject loads the project and indexes both its modules and include graph.
+func loadIncludesProject(ctx context.Context) (*unreal.Project, error) {
+	project, err := unreal.NewProject(gGunrealConfig)
+	if err != nil {
+		return nil, fmt.Errorf("reading project: %w", err)
+	}
+
+	if err := project.IndexModules(ctx); err != nil {
+		return nil, fmt.Errorf("indexing unreal project: %w", err)
+	}
+
+	if err := indexEngineIfRequested(ctx, project); err != nil {
+		return nil, err
+	}
+
+	if err := project.IndexIncludes(ctx); err != nil {
+		return nil, fmt.Errorf("indexing includes: %w", err)
+	}
+
+	return project, nil
+}
@@ -0,0 +1,44 @@
+package project
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	gLintLayoutFlags = struct {
+		json bool
+	}{}
+
+	lintLayoutCmd = &cobra.Command{
+		Use:          "lint-layout",
+		Short:        "Checks that modules follow the Public/Private layout and export macro conventions",
+		Args:         cobra.NoArgs,
+		RunE:         executeLintLayout,
+		SilenceUsage: true,
+	}
+)
+
+func init() {
+	ProjectSectionCmd.AddCommand(lintLayoutCmd)
+
+	lintLayoutCmd.Flags().BoolVar(&gLintLayoutFlags.json, "json", false, "Output as JSON")
+}
+
+func executeLintLayout(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	project, err := loadIncludesProject(ctx)
+	if err != nil {
+		return err
+	}
+
+	diagnostics, err := project.LintLayout(ctx)
+	if err != nil {
+		return fmt.Errorf("linting layout: %w", err)
+	}
+
+	return reportDiagnostics(diagnostics, gLintLayoutFlags.json)
+}
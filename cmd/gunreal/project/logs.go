@@ -0,0 +1,119 @@
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/cristiandonosoc/gunreal/pkg/unreal"
+	"github.com/spf13/cobra"
+)
+
+var (
+	gLogsFlags = struct {
+		follow     bool
+		categories []string
+		verbosity  string
+		since      time.Duration
+		file       string
+		all        bool
+		json       bool
+	}{}
+
+	logsCmd = &cobra.Command{
+		Use:   "logs",
+		Short: "Queries the Unreal logs of the project (Saved/Logs)",
+		Long: `Parses the Unreal logs into records (timestamp, frame, category, verbosity and message, including
+the continuation lines) and filters them. By default the latest log is read.`,
+		Args:         cobra.NoArgs,
+		RunE:         executeLogs,
+		SilenceUsage: true,
+	}
+)
+
+func init() {
+	ProjectSectionCmd.AddCommand(logsCmd)
+
+	logsCmd.Flags().BoolVarP(&gLogsFlags.follow, "follow", "f", false,
+		"Wait for new records in the latest log, following it when it rotates")
+	logsCmd.Flags().StringSliceVar(&gLogsFlags.categories, "category", nil,
+		"Only show these categories (eg. LogNet). Can be repeated")
+	logsCmd.Flags().StringVar(&gLogsFlags.verbosity, "verbosity", "",
+		"Only show records at least this severe (eg. Warning shows warnings, errors and fatals)")
+	logsCmd.Flags().DurationVar(&gLogsFlags.since, "since", 0, "Only show records newer than this (eg. 5m)")
+	logsCmd.Flags().StringVar(&gLogsFlags.file, "file", "", "Log to read instead of the latest one")
+	logsCmd.Flags().BoolVar(&gLogsFlags.all, "all", false, "Read all the logs, from the oldest to the latest")
+	logsCmd.Flags().BoolVar(&gLogsFlags.json, "json", false, "Output one JSON object per record")
+}
+
+func executeLogs(cmd *cobra.Command, args []string) error {
+	filter := &unreal.LogFilter{
+		Categories: gLogsFlags.categories,
+	}
+	if gLogsFlags.verbosity != "" {
+		verbosity, err := unreal.NewLogVerbosity(gLogsFlags.verbosity)
+		if err != nil {
+			return err
+		}
+		filter.MinVerbosity = verbosity
+	}
+	if gLogsFlags.since > 0 {
+		filter.Since = time.Now().Add(-gLogsFlags.since)
+	}
+
+	project, err := unreal.NewProject(gGunrealConfig)
+	if err != nil {
+		return fmt.Errorf("reading project: %w", err)
+	}
+
+	// JSON lines, so that the output can be streamed.
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetEscapeHTML(false)
+	printRecord := func(record *unreal.LogRecord) error {
+		if gLogsFlags.json {
+			return enc.Encode(record)
+		}
+
+		_, err := fmt.Println(record.Raw)
+		return err
+	}
+
+	if gLogsFlags.follow {
+		if gLogsFlags.file != "" || gLogsFlags.all {
+			return fmt.Errorf("--follow only works with the latest log")
+		}
+
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer cancel()
+
+		// With --since, the recent records already in the log are interesting too.
+		return project.FollowLog(ctx, gLogsFlags.since > 0, filter, printRecord)
+	}
+
+	var logs []string
+	switch {
+	case gLogsFlags.file != "":
+		logs = []string{gLogsFlags.file}
+	case gLogsFlags.all:
+		if logs, err = project.LogFiles(); err != nil {
+			return err
+		}
+	default:
+		latest, err := project.LatestLogFile()
+		if err != nil {
+			return err
+		}
+		logs = []string{latest}
+	}
+
+	for _, log := range logs {
+		if err := unreal.ParseLogFile(log, filter, printRecord); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package project
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cristiandonosoc/gunreal/pkg/unreal"
+	"github.com/spf13/cobra"
+)
+
+var (
+	gModuleFlags = struct {
+		plugin       string
+		moduleType   string
+		loadingPhase string
+	}{}
+
+	moduleCmd = &cobra.Command{
+		Use:          "module",
+		Short:        "Edits the modules declared in the .uproject (or a .uplugin)",
+		SilenceUsage: true,
+	}
+
+	moduleAddCmd = &cobra.Command{
+		Use:          "add <Name>",
+		Short:        "Declares an existing module in the .uproject (or a .uplugin)",
+		Args:         cobra.ExactArgs(1),
+		RunE:         executeModuleAdd,
+		SilenceUsage: true,
+	}
+
+	moduleRemoveCmd = &cobra.Command{
+		Use:          "remove <Name>",
+		Short:        "Removes a module declaration from the .uproject (or a .uplugin). Files are not touched",
+		Args:         cobra.ExactArgs(1),
+		RunE:         executeModuleRemove,
+		SilenceUsage: true,
+	}
+)
+
+func init() {
+	ProjectSectionCmd.AddCommand(moduleCmd)
+	moduleCmd.AddCommand(moduleAddCmd)
+	moduleCmd.AddCommand(moduleRemoveCmd)
+
+	moduleCmd.PersistentFlags().StringVar(&gModuleFlags.plugin, "plugin", "",
+		"Edit the .uplugin of this plugin instead of the .uproject")
+	moduleAddCmd.Flags().StringVar(&gModuleFlags.moduleType, "type", "Runtime", "Module type (eg. Runtime, Editor)")
+	moduleAddCmd.Flags().StringVar(&gModuleFlags.loadingPhase, "loading-phase", "Default",
+		"When the module gets loaded (eg. Default, PostEngineInit)")
+}
+
+func executeModuleAdd(cmd *cobra.Command, args []string) error {
+	moduleType, err := unreal.NewModuleHostType(gModuleFlags.moduleType)
+	if err != nil {
+		return err
+	}
+	loadingPhase, err := unreal.NewModuleLoadingPhase(gModuleFlags.loadingPhase)
+	if err != nil {
+		return err
+	}
+
+	project, err := unreal.NewProject(gGunrealConfig)
+	if err != nil {
+		return fmt.Errorf("reading project: %w", err)
+	}
+
+	module := &unreal.UProjectModule{
+		Name:         args[0],
+		Type:         moduleType,
+		LoadingPhase: loadingPhase,
+	}
+	if err := project.RegisterModule(context.Background(), module, gModuleFlags.plugin); err != nil {
+		return fmt.Errorf("adding module %q: %w", module.Name, err)
+	}
+
+	return nil
+}
+
+func executeModuleRemove(cmd *cobra.Command, args []string) error {
+	project, err := unreal.NewProject(gGunrealConfig)
+	if err != nil {
+		return fmt.Errorf("reading project: %w", err)
+	}
+
+	if err := project.UnregisterModule(args[0], gModuleFlags.plugin); err != nil {
+		return fmt.Errorf("removing module %q: %w", args[0], err)
+	}
+
+	return nil
+}
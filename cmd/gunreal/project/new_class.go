@@ -0,0 +1,74 @@
+package project
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cristiandonosoc/gunreal/pkg/unreal"
+	"github.com/spf13/cobra"
+)
+
+var (
+	gNewClassFlags = struct {
+		parent        string
+		parentInclude string
+		private       bool
+		subdir        string
+	}{}
+
+	newClassCmd = &cobra.Command{
+		Use:   "new-class <Module> <ClassName>",
+		Short: "Creates a new UCLASS (header and source) within a module",
+		Long: `Creates a new UCLASS (header and source) within a module.
+
+The class name is given without its Unreal prefix, which comes from the parent class (eg. MyActor
+with --parent AActor creates AMyActor).
+
+The header goes into Public/ (or Private/ with --private) and the source into Private/. Custom
+templates can be configured per parent class in the scaffolding section of gunreal.yml.`,
+		Args:         cobra.ExactArgs(2),
+		RunE:         executeNewClass,
+		SilenceUsage: true,
+	}
+)
+
+func init() {
+	ProjectSectionCmd.AddCommand(newClassCmd)
+
+	newClassCmd.Flags().StringVar(&gNewClassFlags.parent, "parent", "UObject",
+		"Parent class (eg. AActor, UActorComponent, UObject, UInterface or a class of the project)")
+	newClassCmd.Flags().StringVar(&gNewClassFlags.parentInclude, "parent-include", "",
+		"Header that declares the parent class. Only needed for classes gunreal cannot find")
+	newClassCmd.Flags().BoolVar(&gNewClassFlags.private, "private", false,
+		"Put the header in Private/, making the class internal to the module")
+	newClassCmd.Flags().StringVar(&gNewClassFlags.subdir, "subdir", "",
+		"Directory within Public/ and Private/ to put the files in")
+}
+
+func executeNewClass(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	project, err := unreal.NewProject(gGunrealConfig)
+	if err != nil {
+		return fmt.Errorf("reading project: %w", err)
+	}
+
+	options := &unreal.NewClassOptions{
+		Module:        args[0],
+		Name:          args[1],
+		Parent:        gNewClassFlags.parent,
+		ParentInclude: gNewClassFlags.parentInclude,
+		Private:       gNewClassFlags.private,
+		Subdir:        gNewClassFlags.subdir,
+	}
+	created, err := project.NewClass(ctx, options)
+	if err != nil {
+		return fmt.Errorf("creating class: %w", err)
+	}
+
+	for _, path := range created {
+		fmt.Println("Created", path)
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package project
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cristiandonosoc/gunreal/pkg/unreal"
+	"github.com/spf13/cobra"
+)
+
+var (
+	gNewModuleFlags = struct {
+		plugin       string
+		moduleType   string
+		loadingPhase string
+	}{}
+
+	newModuleCmd = &cobra.Command{
+		Use:          "new-module <Name>",
+		Short:        "Creates a new module and registers it in the .uproject (or .uplugin)",
+		Args:         cobra.ExactArgs(1),
+		RunE:         executeNewModule,
+		SilenceUsage: true,
+	}
+)
+
+func init() {
+	ProjectSectionCmd.AddCommand(newModuleCmd)
+
+	newModuleCmd.Flags().StringVar(&gNewModuleFlags.plugin, "plugin", "",
+		"Plugin to create the module in. If empty, the module is created in the project Source")
+	newModuleCmd.Flags().StringVar(&gNewModuleFlags.moduleType, "type", "Runtime",
+		"Module type (eg. Runtime, Editor)")
+	newModuleCmd.Flags().StringVar(&gNewModuleFlags.loadingPhase, "loading-phase", "Default",
+		"When the module gets loaded (eg. Default, PostEngineInit)")
+}
+
+func executeNewModule(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	project, err := unreal.NewProject(gGunrealConfig)
+	if err != nil {
+		return fmt.Errorf("reading project: %w", err)
+	}
+
+	options := &unreal.NewModuleOptions{
+		Name:         args[0],
+		Plugin:       gNewModuleFlags.plugin,
+		Type:         gNewModuleFlags.moduleType,
+		LoadingPhase: gNewModuleFlags.loadingPhase,
+	}
+	module, err := project.NewModule(ctx, options)
+	if err != nil {
+		return fmt.Errorf("creating module: %w", err)
+	}
+
+	fmt.Printf("Created module %s at %s\n", module.Name, module.BaseDir)
+	for _, file := range module.Files {
+		fmt.Println("-", file)
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package project
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cristiandonosoc/gunreal/pkg/unreal"
+	"github.com/spf13/cobra"
+)
+
+var (
+	gNewPluginFlags = struct {
+		template    string
+		description string
+		category    string
+		createdBy   string
+		content     bool
+		icon        string
+	}{}
+
+	newPluginCmd = &cobra.Command{
+		Use:          "new-plugin <Name>",
+		Short:        "Creates a new plugin and enables it in the .uproject",
+		Args:         cobra.ExactArgs(1),
+		RunE:         executeNewPlugin,
+		SilenceUsage: true,
+	}
+)
+
+func init() {
+	ProjectSectionCmd.AddCommand(newPluginCmd)
+
+	newPluginCmd.Flags().StringVar(&gNewPluginFlags.template, "template", "blank",
+		"Modules to create: blank (runtime module), editor-only or runtime-editor")
+	newPluginCmd.Flags().StringVar(&gNewPluginFlags.description, "description", "", "Description of the plugin")
+	newPluginCmd.Flags().StringVar(&gNewPluginFlags.category, "category", "Other", "Category of the plugin")
+	newPluginCmd.Flags().StringVar(&gNewPluginFlags.createdBy, "created-by", "", "Author of the plugin")
+	newPluginCmd.Flags().BoolVar(&gNewPluginFlags.content, "content", false,
+		"Create a Content directory and allow the plugin to hold content")
+	newPluginCmd.Flags().StringVar(&gNewPluginFlags.icon, "icon", "",
+		"PNG to use as the plugin icon (copied to Resources/Icon128.png)")
+}
+
+func executeNewPlugin(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	template, err := unreal.NewPluginTemplate(gNewPluginFlags.template)
+	if err != nil {
+		return fmt.Errorf("parsing template: %w", err)
+	}
+
+	project, err := unreal.NewProject(gGunrealConfig)
+	if err != nil {
+		return fmt.Errorf("reading project: %w", err)
+	}
+
+	options := &unreal.NewPluginOptions{
+		Name:        args[0],
+		Template:    template,
+		Description: gNewPluginFlags.description,
+		Category:    gNewPluginFlags.category,
+		CreatedBy:   gNewPluginFlags.createdBy,
+		Content:     gNewPluginFlags.content,
+		IconPath:    gNewPluginFlags.icon,
+	}
+	plugin, err := project.NewPlugin(ctx, options)
+	if err != nil {
+		return fmt.Errorf("creating plugin: %w", err)
+	}
+
+	fmt.Printf("Created plugin %s at %s\n", plugin.Name, plugin.BaseDir)
+	for _, module := range plugin.Descriptor.Modules {
+		fmt.Printf("- MODULE: %s (%s)\n", module.Name, module.Type)
+	}
+
+	return nil
+}
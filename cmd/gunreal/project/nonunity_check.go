@@ -0,0 +1,114 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cristiandonosoc/gunreal/pkg/unreal"
+	"github.com/spf13/cobra"
+)
+
+var (
+	gNonUnityCheckFlags = struct {
+		since         string
+		target        string
+		platform      string
+		configuration string
+		noPCH         bool
+		dryRun        bool
+		json          bool
+	}{}
+
+	nonUnityCheckCmd = &cobra.Command{
+		Use:          "nonunity-check",
+		Short:        "Builds the modules touched since a git ref without unity batching to find missing includes",
+		Args:         cobra.NoArgs,
+		RunE:         executeNonUnityCheck,
+		SilenceUsage: true,
+	}
+)
+
+func init() {
+	ProjectSectionCmd.AddCommand(nonUnityCheckCmd)
+
+	nonUnityCheckCmd.Flags().StringVar(&gNonUnityCheckFlags.since, "since", "origin/main",
+		"Git ref to compare against to find the touched modules")
+	nonUnityCheckCmd.Flags().StringVar(&gNonUnityCheckFlags.target, "target", "",
+		"UBT target to build. Defaults to <ProjectName>Editor")
+	nonUnityCheckCmd.Flags().StringVar(&gNonUnityCheckFlags.platform, "platform", "win64", "Platform to build")
+	nonUnityCheckCmd.Flags().StringVar(&gNonUnityCheckFlags.configuration, "configuration", "Development",
+		"Configuration to build")
+	nonUnityCheckCmd.Flags().BoolVar(&gNonUnityCheckFlags.noPCH, "no-pch", false,
+		"Also disable precompiled headers, which can hide missing includes too")
+	nonUnityCheckCmd.Flags().BoolVar(&gNonUnityCheckFlags.dryRun, "dry-run", false,
+		"Only print the touched modules and the UBT arguments")
+	nonUnityCheckCmd.Flags().BoolVar(&gNonUnityCheckFlags.json, "json", false, "Output as JSON")
+}
+
+func executeNonUnityCheck(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	platform, err := unreal.NewUnrealPlatform(gNonUnityCheckFlags.platform)
+	if err != nil {
+		return fmt.Errorf("parsing platform: %w", err)
+	}
+
+	project, err := unreal.NewProject(gGunrealConfig)
+	if err != nil {
+		return fmt.Errorf("reading project: %w", err)
+	}
+
+	target := gNonUnityCheckFlags.target
+	if target == "" {
+		target = project.Config.ProjectName + "Editor"
+	}
+
+	options := &unreal.NonUnityCheckOptions{
+		Since:         gNonUnityCheckFlags.since,
+		Target:        target,
+		Platform:      platform,
+		Configuration: gNonUnityCheckFlags.configuration,
+		DisablePCH:    gNonUnityCheckFlags.noPCH,
+		DryRun:        gNonUnityCheckFlags.dryRun,
+		Output:        os.Stdout,
+	}
+	// Keep stdout clean for the JSON output.
+	if gNonUnityCheckFlags.json {
+		options.Output = os.Stderr
+	}
+	result, err := project.NonUnityCheck(ctx, options)
+	if err != nil {
+		return fmt.Errorf("running non-unity check: %w", err)
+	}
+
+	if len(result.Modules) == 0 {
+		if gNonUnityCheckFlags.json {
+			return printJSON([]*unreal.Diagnostic{})
+		}
+
+		fmt.Printf("No modules touched since %q\n", options.Since)
+		return nil
+	}
+
+	if gNonUnityCheckFlags.dryRun {
+		names := make([]string, 0, len(result.Modules))
+		for _, module := range result.Modules {
+			names = append(names, module.Name)
+		}
+
+		if gNonUnityCheckFlags.json {
+			return printJSON(map[string]any{
+				"modules": names,
+				"args":    result.Args,
+			})
+		}
+
+		fmt.Println("Modules:", strings.Join(names, ", "))
+		fmt.Println("UBT arguments:", strings.Join(result.Args, " "))
+		return nil
+	}
+
+	return reportDiagnostics(result.Diagnostics, gNonUnityCheckFlags.json)
+}
@@ -0,0 +1,21 @@
+package project
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// printJSON outputs |v| as indented JSON to stdout.
+// HTML escaping is disabled, as C++ types are full of angle brackets.
+func printJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("encoding json: %w", err)
+	}
+
+	return nil
+}
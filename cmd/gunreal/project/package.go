@@ -0,0 +1,116 @@
+package project
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cristiandonosoc/gunreal/pkg/unreal"
+	"github.com/spf13/cobra"
+)
+
+var (
+	gPackageFlags = struct {
+		quiet  bool
+		dryRun bool
+		json   bool
+	}{}
+
+	packageCmd = &cobra.Command{
+		Use:   "package <profile>",
+		Short: "Packages the project with BuildCookRun, using a package profile from the config file",
+		Long: `Runs UAT BuildCookRun with the platform, configuration, cook flavor, pak/iostore, archive
+directory and maps of one of the package_profiles in the config file. The stages are followed as
+they run and the errors found in the output are summarized at the end.`,
+		Args:         cobra.ExactArgs(1),
+		RunE:         executePackage,
+		SilenceUsage: true,
+	}
+)
+
+func init() {
+	ProjectSectionCmd.AddCommand(packageCmd)
+
+	packageCmd.Flags().BoolVar(&gPackageFlags.quiet, "quiet", false,
+		"Do not print the UAT output, only the stages and the errors")
+	packageCmd.Flags().BoolVar(&gPackageFlags.dryRun, "dry-run", false, "Only print the UAT arguments")
+	packageCmd.Flags().BoolVar(&gPackageFlags.json, "json", false, "Output the result as JSON")
+}
+
+func executePackage(cmd *cobra.Command, args []string) error {
+	profile, err := gGunrealConfig.FindPackageProfile(args[0])
+	if err != nil {
+		return err
+	}
+
+	project, err := unreal.NewProject(gGunrealConfig)
+	if err != nil {
+		return fmt.Errorf("reading project: %w", err)
+	}
+
+	if gPackageFlags.dryRun {
+		uatArgs, err := project.PackageArgs(profile)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(strings.Join(uatArgs, " "))
+		return nil
+	}
+
+	// Keep stdout clean for the JSON output.
+	var output io.Writer = os.Stdout
+	if gPackageFlags.json {
+		output = os.Stderr
+	}
+
+	options := &unreal.PackageOptions{
+		Profile: profile,
+		OnStage: func(stage *unreal.PackageStage) {
+			fmt.Fprintf(os.Stderr, "==> %s\n", formatPackageStage(stage))
+		},
+	}
+	if !gPackageFlags.quiet {
+		options.Output = output
+	}
+
+	result, err := project.Package(options)
+	if err != nil {
+		return fmt.Errorf("packaging: %w", err)
+	}
+
+	if gPackageFlags.json {
+		if err := printJSON(result); err != nil {
+			return err
+		}
+	} else {
+		fmt.Println()
+		fmt.Println("STAGES:", len(result.Stages))
+		for _, stage := range result.Stages {
+			fmt.Println("-", formatPackageStage(stage))
+		}
+
+		if len(result.Diagnostics) > 0 {
+			fmt.Println()
+			fmt.Println("ERRORS:", len(result.Diagnostics))
+			for _, diagnostic := range result.Diagnostics {
+				fmt.Println("-", diagnostic)
+			}
+		}
+	}
+
+	if result.Error != "" {
+		return fmt.Errorf("packaging with profile %q failed: %s", profile.Name, result.Error)
+	}
+
+	return nil
+}
+
+func formatPackageStage(stage *unreal.PackageStage) string {
+	if stage.Status == unreal.PackageStageStatus_Started {
+		return fmt.Sprintf("%s %s", stage.Name, stage.Status)
+	}
+	return fmt.Sprintf("%s %s (%s)", stage.Name, stage.Status, stage.Duration.Round(time.Second))
+}
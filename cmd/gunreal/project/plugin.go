@@ -0,0 +1,59 @@
+package project
+
+import (
+	"fmt"
+
+	"github.com/cristiandonosoc/gunreal/pkg/unreal"
+	"github.com/spf13/cobra"
+)
+
+var (
+	pluginCmd = &cobra.Command{
+		Use:          "plugin",
+		Short:        "Edits the plugin references of the .uproject",
+		SilenceUsage: true,
+	}
+
+	pluginEnableCmd = &cobra.Command{
+		Use:          "enable <Name>",
+		Short:        "Enables a plugin in the .uproject",
+		Args:         cobra.ExactArgs(1),
+		RunE:         executePluginEnable,
+		SilenceUsage: true,
+	}
+
+	pluginDisableCmd = &cobra.Command{
+		Use:          "disable <Name>",
+		Short:        "Disables a plugin in the .uproject",
+		Args:         cobra.ExactArgs(1),
+		RunE:         executePluginDisable,
+		SilenceUsage: true,
+	}
+)
+
+func init() {
+	ProjectSectionCmd.AddCommand(pluginCmd)
+	pluginCmd.AddCommand(pluginEnableCmd)
+	pluginCmd.AddCommand(pluginDisableCmd)
+}
+
+func executePluginEnable(cmd *cobra.Command, args []string) error {
+	return setPluginEnabled(args[0], true)
+}
+
+func executePluginDisable(cmd *cobra.Command, args []string) error {
+	return setPluginEnabled(args[0], false)
+}
+
+func setPluginEnabled(name string, enabled bool) error {
+	project, err := unreal.NewProject(gGunrealConfig)
+	if err != nil {
+		return fmt.Errorf("reading project: %w", err)
+	}
+
+	if err := project.SetPluginEnabled(name, enabled); err != nil {
+		return fmt.Errorf("editing plugin %q: %w", name, err)
+	}
+
+	return nil
+}
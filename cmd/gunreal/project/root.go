@@ -12,6 +12,7 @@ import (
 var (
 	gFlags = struct {
 		configPath string
+		engine     bool
 	}{}
 
 	gGunrealConfig *gunreal_config.GunrealConfig
@@ -37,4 +38,6 @@ var (
 func init() {
 	ProjectSectionCmd.PersistentFlags().StringVar(&gFlags.configPath, "config-path", "gunreal.yml",
 		"Path the config file")
+	ProjectSectionCmd.PersistentFlags().BoolVar(&gFlags.engine, "engine", false,
+		"Also index the engine modules, so that queries can resolve them (cached per engine version)")
 }
@@ -0,0 +1,93 @@
+package project
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cristiandonosoc/gunreal/pkg/unreal"
+	"github.com/spf13/cobra"
+)
+
+var (
+	gRunFlags = struct {
+		dryRun bool
+	}{}
+
+	runCmd = &cobra.Command{
+		Use:   "run <editor|game|server|commandlet> [commandlet] [profile] [-- extra args]",
+		Short: "Launches the editor, game, server or a commandlet for the project",
+		Long: `Launches the project with the UnrealEditor of the configured engine.
+
+The optional profile names one of the launch_profiles in the config file, which add a map, -log,
+-ExecCmds, -ini overrides and any other arguments. Commandlets take the commandlet name first.
+Anything after -- is passed as is.`,
+		Args:         cobra.MinimumNArgs(1),
+		RunE:         executeRun,
+		SilenceUsage: true,
+	}
+)
+
+func init() {
+	ProjectSectionCmd.AddCommand(runCmd)
+
+	runCmd.Flags().BoolVar(&gRunFlags.dryRun, "dry-run", false, "Only print the command line")
+}
+
+func executeRun(cmd *cobra.Command, args []string) error {
+	// Everything after "--" goes to Unreal.
+	var extraArgs []string
+	if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+		extraArgs = args[dash:]
+		args = args[:dash]
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("no launch mode given")
+	}
+
+	mode, err := unreal.NewLaunchMode(args[0])
+	if err != nil {
+		return err
+	}
+	args = args[1:]
+
+	options := &unreal.RunOptions{
+		Mode:      mode,
+		ExtraArgs: extraArgs,
+	}
+
+	if mode == unreal.LaunchMode_Commandlet {
+		if len(args) == 0 {
+			return fmt.Errorf("no commandlet given")
+		}
+		options.Commandlet = args[0]
+		args = args[1:]
+	}
+
+	if len(args) > 1 {
+		return fmt.Errorf("unexpected arguments: %s", strings.Join(args[1:], " "))
+	}
+	if len(args) == 1 {
+		profile, err := gGunrealConfig.FindLaunchProfile(args[0])
+		if err != nil {
+			return err
+		}
+		options.Profile = profile
+	}
+
+	project, err := unreal.NewProject(gGunrealConfig)
+	if err != nil {
+		return fmt.Errorf("reading project: %w", err)
+	}
+
+	if gRunFlags.dryRun {
+		launchArgs, err := project.LaunchArgs(options)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(strings.Join(launchArgs, " "))
+		return nil
+	}
+
+	return project.Run(options)
+}
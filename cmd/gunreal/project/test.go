@@ -0,0 +1,134 @@
+package project
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/cristiandonosoc/gunreal/pkg/unreal"
+	"github.com/spf13/cobra"
+)
+
+var (
+	gTestFlags = struct {
+		filter     string
+		report     string
+		reportDir  string
+		fromReport string
+		allowEmpty bool
+		dryRun     bool
+	}{}
+
+	testCmd = &cobra.Command{
+		Use:   "test [-- extra args]",
+		Short: "Runs the automation tests in a headless editor",
+		Long: `Runs the automation tests with UnrealEditor-Cmd without rendering (-nullrhi), so that it works
+on CI machines. The exported report is summarized and optionally converted into JUnit XML.
+Fails if any test fails, or if no test ran (eg. a filter with a typo) unless --allow-empty is set.`,
+		RunE:         executeTest,
+		SilenceUsage: true,
+	}
+)
+
+func init() {
+	ProjectSectionCmd.AddCommand(testCmd)
+
+	testCmd.Flags().StringVar(&gTestFlags.filter, "filter", "Project",
+		"Tests to run (eg. Project.Gameplay). Empty runs all the tests, including the engine ones")
+	testCmd.Flags().StringVar(&gTestFlags.report, "report", "", "Where to write the JUnit XML report")
+	testCmd.Flags().StringVar(&gTestFlags.reportDir, "report-dir", "",
+		"Where Unreal exports its report. Defaults to <ProjectDir>/Saved/Automation/Gunreal")
+	testCmd.Flags().StringVar(&gTestFlags.fromReport, "from-report", "",
+		"Do not run the tests, only process the report already exported in this directory")
+	testCmd.Flags().BoolVar(&gTestFlags.allowEmpty, "allow-empty", false,
+		"Do not fail when the filter does not match any test")
+	testCmd.Flags().BoolVar(&gTestFlags.dryRun, "dry-run", false, "Only print the command line")
+}
+
+func executeTest(cmd *cobra.Command, args []string) error {
+	project, err := unreal.NewProject(gGunrealConfig)
+	if err != nil {
+		return fmt.Errorf("reading project: %w", err)
+	}
+
+	var report *unreal.AutomationReport
+	if gTestFlags.fromReport != "" {
+		report, err = unreal.LoadAutomationReport(gTestFlags.fromReport)
+		if err != nil {
+			return err
+		}
+	} else {
+		reportDir := gTestFlags.reportDir
+		if reportDir == "" {
+			reportDir = filepath.Join(project.ProjectDir(), "Saved", "Automation", "Gunreal")
+		}
+		reportDir, err = filepath.Abs(reportDir)
+		if err != nil {
+			return fmt.Errorf("abs %q: %w", reportDir, err)
+		}
+
+		options := &unreal.AutomationTestOptions{
+			Filter:    gTestFlags.filter,
+			ReportDir: reportDir,
+			ExtraArgs: args,
+		}
+
+		if gTestFlags.dryRun {
+			testArgs, err := project.AutomationTestArgs(options)
+			if err != nil {
+				return err
+			}
+
+			for _, arg := range testArgs {
+				fmt.Println(arg)
+			}
+			return nil
+		}
+
+		report, err = project.RunAutomationTests(options)
+		if err != nil {
+			return err
+		}
+	}
+
+	if gTestFlags.report != "" {
+		data, err := report.JUnit(gGunrealConfig.ProjectName)
+		if err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(gTestFlags.report, data, 0644); err != nil {
+			return fmt.Errorf("writing %q: %w", gTestFlags.report, err)
+		}
+	}
+
+	printAutomationReport(report)
+
+	if failed := report.FailedTests(); len(failed) > 0 {
+		return fmt.Errorf("%d tests failed", len(failed))
+	}
+	if len(report.Tests) == 0 && !gTestFlags.allowEmpty {
+		if gTestFlags.fromReport != "" {
+			return fmt.Errorf("no tests in the report at %q", gTestFlags.fromReport)
+		}
+		return fmt.Errorf("no tests ran with filter %q", gTestFlags.filter)
+	}
+
+	return nil
+}
+
+func printAutomationReport(report *unreal.AutomationReport) {
+	fmt.Println()
+	for _, test := range report.FailedTests() {
+		fmt.Printf("FAILED: %s\n", test.FullPath)
+		for _, entry := range test.ErrorEntries() {
+			fmt.Printf("  %s\n", entry)
+		}
+	}
+	if len(report.Tests) == 0 {
+		fmt.Println("No tests ran. Does the filter match any test?")
+	}
+
+	fmt.Println()
+	fmt.Println(report.Summary())
+}
@@ -0,0 +1,131 @@
+package project
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cristiandonosoc/gunreal/pkg/unreal"
+	"github.com/spf13/cobra"
+)
+
+var (
+	gTypesFlags = struct {
+		kind        string
+		specifiers  []string
+		derivesFrom string
+		module      string
+		members     bool
+		json        bool
+	}{}
+
+	typesCmd = &cobra.Command{
+		Use:          "types [name]",
+		Short:        "Queries the UHT reflected types (UCLASS, USTRUCT, UENUM, ...) of the project",
+		Args:         cobra.MaximumNArgs(1),
+		RunE:         executeTypes,
+		SilenceUsage: true,
+	}
+)
+
+func init() {
+	ProjectSectionCmd.AddCommand(typesCmd)
+
+	typesCmd.Flags().StringVar(&gTypesFlags.kind, "kind", "",
+		"Only show types of this kind (class, struct, enum, interface, delegate)")
+	typesCmd.Flags().StringSliceVar(&gTypesFlags.specifiers, "specifier", nil,
+		"Only show types declared with all these specifiers (eg. Blueprintable)")
+	typesCmd.Flags().StringVar(&gTypesFlags.derivesFrom, "derives-from", "",
+		"Only show types that derive (directly or not) from this type")
+	typesCmd.Flags().StringVar(&gTypesFlags.module, "module", "",
+		"Only show types declared in this module")
+	typesCmd.Flags().BoolVar(&gTypesFlags.members, "members", false,
+		"Also list the UFUNCTION and UPROPERTY members")
+	typesCmd.Flags().BoolVar(&gTypesFlags.json, "json", false, "Output as JSON")
+}
+
+func executeTypes(cmd *cobra.Command, args []string) error {
+	var kind unreal.ReflectedKind
+	if gTypesFlags.kind != "" {
+		k, err := unreal.NewReflectedKind(gTypesFlags.kind)
+		if err != nil {
+			return fmt.Errorf("parsing kind: %w", err)
+		}
+		kind = k
+	}
+
+	project, err := loadReflectedProject(context.Background())
+	if err != nil {
+		return err
+	}
+
+	var types []*unreal.ReflectedType
+	for _, rt := range project.ReflectedTypes() {
+		if len(args) > 0 && rt.Name != args[0] {
+			continue
+		}
+
+		if kind != "" && rt.Kind != kind {
+			continue
+		}
+
+		if gTypesFlags.module != "" && rt.Module.Name != gTypesFlags.module {
+			continue
+		}
+
+		if gTypesFlags.derivesFrom != "" && !project.DerivesFrom(rt, gTypesFlags.derivesFrom) {
+			continue
+		}
+
+		matches := true
+		for _, specifier := range gTypesFlags.specifiers {
+			if !rt.HasSpecifier(specifier) {
+				matches = false
+				break
+			}
+		}
+		if !matches {
+			continue
+		}
+
+		types = append(types, rt)
+	}
+
+	if gTypesFlags.json {
+		return printJSON(types)
+	}
+
+	for _, rt := range types {
+		fmt.Printf("%s [module:%s] %s:%d\n", rt, rt.Module.Name, rt.File, rt.Line)
+
+		if !gTypesFlags.members {
+			continue
+		}
+
+		for _, function := range rt.Functions {
+			fmt.Printf("  - UFUNCTION %s %s() %v\n", function.Type, function.Name, function.Specifiers)
+		}
+		for _, property := range rt.Properties {
+			fmt.Printf("  - UPROPERTY %s %s %v\n", property.Type, property.Name, property.Specifiers)
+		}
+	}
+
+	return nil
+}
+
+// loadReflectedProject loads the project and indexes both its modules and reflected types.
+func loadReflectedProject(ctx context.Context) (*unreal.Project, error) {
+	project, err := unreal.NewProject(gGunrealConfig)
+	if err != nil {
+		return nil, fmt.Errorf("reading project: %w", err)
+	}
+
+	if err := project.IndexModules(ctx); err != nil {
+		return nil, fmt.Errorf("indexing unreal project: %w", err)
+	}
+
+	if err := project.IndexReflectedTypes(ctx); err != nil {
+		return nil, fmt.Errorf("indexing reflected types: %w", err)
+	}
+
+	return project, nil
+}
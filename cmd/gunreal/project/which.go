@@ -0,0 +1,62 @@
+package project
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cristiandonosoc/gunreal/pkg/unreal"
+	"github.com/spf13/cobra"
+)
+
+var (
+	gWhichFlags = struct {
+		json bool
+	}{}
+
+	whichCmd = &cobra.Command{
+		Use:   "which <module|file|include>",
+		Short: "Tells which module (and plugin) a module name, file or include path belongs to",
+		Long: `Tells which module (and plugin) a module name, file or include path belongs to.
+
+Include paths are written as in an #include (eg. GameFramework/Actor.h). Use --engine to also
+search the engine modules.`,
+		Args:         cobra.ExactArgs(1),
+		RunE:         executeWhich,
+		SilenceUsage: true,
+	}
+)
+
+func init() {
+	ProjectSectionCmd.AddCommand(whichCmd)
+
+	whichCmd.Flags().BoolVar(&gWhichFlags.json, "json", false, "Output as JSON")
+}
+
+func executeWhich(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	project, err := unreal.NewProject(gGunrealConfig)
+	if err != nil {
+		return fmt.Errorf("reading project: %w", err)
+	}
+
+	if err := project.IndexModules(ctx); err != nil {
+		return fmt.Errorf("indexing unreal project: %w", err)
+	}
+
+	if err := indexEngineIfRequested(ctx, project); err != nil {
+		return err
+	}
+
+	result, err := project.Which(args[0])
+	if err != nil {
+		return err
+	}
+
+	if gWhichFlags.json {
+		return printJSON(result)
+	}
+
+	fmt.Println(result)
+	return nil
+}
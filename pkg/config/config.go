@@ -24,6 +24,20 @@ type GunrealConfig struct {
 
 	EditorConfig *GunrealEditorConfig `yaml:"editor"`
 
+	// *** Scaffolding fields ***
+
+	ScaffoldingConfig *GunrealScaffoldingConfig `yaml:"scaffolding"`
+
+	// *** Launch fields ***
+
+	// (optional) Named argument sets for |gunreal project run|.
+	LaunchProfiles []*GunrealLaunchProfile `yaml:"launch_profiles"`
+
+	// *** Package fields ***
+
+	// (optional) Named BuildCookRun configurations for |gunreal project package|.
+	PackageProfiles []*GunrealPackageProfile `yaml:"package_profiles"`
+
 	Path string
 }
 
@@ -69,6 +83,22 @@ func (gc *GunrealConfig) Describe() string {
 		sb.WriteString(gc.EditorConfig.Describe())
 	}
 
+	if len(gc.LaunchProfiles) > 0 {
+		sb.WriteString("\n")
+		sb.WriteString("LAUNCH PROFILES ----------------------------------------------------------\n\n")
+		for _, profile := range gc.LaunchProfiles {
+			sb.WriteString(fmt.Sprintf("- %s\n", profile.Name))
+		}
+	}
+
+	if len(gc.PackageProfiles) > 0 {
+		sb.WriteString("\n")
+		sb.WriteString("PACKAGE PROFILES ---------------------------------------------------------\n\n")
+		for _, profile := range gc.PackageProfiles {
+			sb.WriteString(fmt.Sprintf("- %s (%s %s)\n", profile.Name, profile.Platform, profile.Configuration))
+		}
+	}
+
 	return sb.String()
 }
 
@@ -86,6 +116,18 @@ func (gc *GunrealConfig) resolve() error {
 		return fmt.Errorf("reading editor config: %w", err)
 	}
 
+	if err := resolveScaffoldingConfig(gc.Path, gc.ScaffoldingConfig); err != nil {
+		return fmt.Errorf("reading scaffolding config: %w", err)
+	}
+
+	if err := resolveLaunchProfiles(gc.LaunchProfiles); err != nil {
+		return fmt.Errorf("reading launch profiles: %w", err)
+	}
+
+	if err := resolvePackageProfiles(gc.Path, gc.PackageProfiles); err != nil {
+		return fmt.Errorf("reading package profiles: %w", err)
+	}
+
 	return nil
 }
 
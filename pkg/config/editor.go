@@ -36,6 +36,9 @@ type GunrealEditorConfig struct {
 	// UBTDll will normally be discovered via the editor.
 	UBTDll string
 
+	// UATDll is the AutomationTool, discovered via the editor. Empty if it has not been built yet.
+	UATDll string
+
 	// For internal tracking information mostly.
 	BuildVersionFile *buildVersionJson
 }
@@ -60,6 +63,7 @@ func (gec *GunrealEditorConfig) Describe() string {
 	sb.WriteString(fmt.Sprintf("- INSTALLED: %t\n", gec.Installed))
 	sb.WriteString(fmt.Sprintf("- DOTNET: %s\n", gec.Dotnet))
 	sb.WriteString(fmt.Sprintf("- UBT DLL: %s\n", gec.UBTDll))
+	sb.WriteString(fmt.Sprintf("- UAT DLL: %s\n", gec.UATDll))
 
 	return sb.String()
 }
@@ -101,6 +105,12 @@ func resolveEditorConfig(configPath string, gec *GunrealEditorConfig) error {
 	}
 	gec.UBTDll = ubt
 
+	uat, err := resolveUAT(gec)
+	if err != nil {
+		return fmt.Errorf("resolving uat: %w", err)
+	}
+	gec.UATDll = uat
+
 	return nil
 }
 
@@ -186,3 +196,23 @@ func resolveUBT(configPath string, gec *GunrealEditorConfig) (string, error) {
 
 	return "", fmt.Errorf("unsupported version %q", gec.Version)
 }
+
+// resolveUAT finds the AutomationTool. Unlike UBT, it is only needed for packaging and source builds
+// only get it after running RunUAT once, so not finding it is not an error.
+func resolveUAT(gec *GunrealEditorConfig) (string, error) {
+	if gec.Version.LessThan(gVersion_5_4) {
+		uatPath := filepath.Join(gec.EditorDir, "Engine", "Binaries", "DotNET", "AutomationTool", "AutomationTool.dll")
+
+		_, found, err := files.StatFile(uatPath)
+		if err != nil {
+			return "", fmt.Errorf("statting %q: %w", uatPath, err)
+		}
+		if !found {
+			return "", nil
+		}
+
+		return uatPath, nil
+	}
+
+	return "", fmt.Errorf("unsupported version %q", gec.Version)
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GunrealLaunchProfile is a named set of arguments used to launch the editor, game or server.
+type GunrealLaunchProfile struct {
+	Name string `yaml:"name"`
+
+	// (optional) Map to open (eg. /Game/Maps/Lobby).
+	Map string `yaml:"map"`
+	// (optional) Whether to open a log window (-log).
+	Log bool `yaml:"log"`
+	// (optional) Console commands to run at startup (-ExecCmds).
+	ExecCmds []string `yaml:"exec_cmds"`
+	// (optional) Config overrides in the form <File>:[<Section>]:<Key>=<Value> (-ini).
+	Ini []string `yaml:"ini"`
+	// (optional) Any other argument that is passed as is.
+	Args []string `yaml:"args"`
+}
+
+// FindLaunchProfile returns the launch profile called |name|.
+func (gc *GunrealConfig) FindLaunchProfile(name string) (*GunrealLaunchProfile, error) {
+	var names []string
+	for _, profile := range gc.LaunchProfiles {
+		if profile.Name == name {
+			return profile, nil
+		}
+		names = append(names, profile.Name)
+	}
+
+	if len(names) == 0 {
+		return nil, fmt.Errorf("launch profile %q not found (no launch_profiles in %q)", name, gc.Path)
+	}
+	return nil, fmt.Errorf("launch profile %q not found (have %s)", name, strings.Join(names, ", "))
+}
+
+func resolveLaunchProfiles(profiles []*GunrealLaunchProfile) error {
+	seen := map[string]struct{}{}
+	for i, profile := range profiles {
+		if profile.Name == "" {
+			return fmt.Errorf("launch profile %d: name not set", i)
+		}
+
+		if _, ok := seen[profile.Name]; ok {
+			return fmt.Errorf("launch profile %q defined more than once", profile.Name)
+		}
+		seen[profile.Name] = struct{}{}
+
+		for _, ini := range profile.Ini {
+			if !strings.Contains(ini, ":") || !strings.Contains(ini, "=") {
+				return fmt.Errorf("launch profile %q: ini override %q is not <File>:[<Section>]:<Key>=<Value>",
+					profile.Name, ini)
+			}
+		}
+	}
+
+	return nil
+}
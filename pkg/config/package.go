@@ -0,0 +1,83 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GunrealPackageProfile is a named BuildCookRun configuration used by |gunreal project package|.
+type GunrealPackageProfile struct {
+	Name string `yaml:"name"`
+
+	// Platform to package for, as UAT names it (eg. Win64, Android or LinuxArm64).
+	Platform string `yaml:"platform"`
+	// (optional) Configuration to build (eg. Shipping). Defaults to Development.
+	Configuration string `yaml:"configuration"`
+	// (optional) Target to build, if the project has more than one game target.
+	Target string `yaml:"target"`
+	// (optional) Cook flavor for platforms that have them (eg. ASTC for Android).
+	CookFlavor string `yaml:"cook_flavor"`
+	// (optional) Whether to put the content in .pak files.
+	Pak bool `yaml:"pak"`
+	// (optional) Whether to use the I/O store container files. Requires |Pak|.
+	IoStore bool `yaml:"iostore"`
+	// (optional) Where to archive the packaged build. Relative paths are resolved against the config
+	// file. If not set, the build is not archived.
+	ArchiveDirectory string `yaml:"archive_directory"`
+	// (optional) Maps to cook. If not set, the maps from the project settings are cooked.
+	Maps []string `yaml:"maps"`
+	// (optional) Any other argument that is passed to BuildCookRun as is.
+	Args []string `yaml:"args"`
+}
+
+// FindPackageProfile returns the package profile called |name|.
+func (gc *GunrealConfig) FindPackageProfile(name string) (*GunrealPackageProfile, error) {
+	var names []string
+	for _, profile := range gc.PackageProfiles {
+		if profile.Name == name {
+			return profile, nil
+		}
+		names = append(names, profile.Name)
+	}
+
+	if len(names) == 0 {
+		return nil, fmt.Errorf("package profile %q not found (no package_profiles in %q)", name, gc.Path)
+	}
+	return nil, fmt.Errorf("package profile %q not found (have %s)", name, strings.Join(names, ", "))
+}
+
+func resolvePackageProfiles(configPath string, profiles []*GunrealPackageProfile) error {
+	seen := map[string]struct{}{}
+	for i, profile := range profiles {
+		if profile.Name == "" {
+			return fmt.Errorf("package profile %d: name not set", i)
+		}
+
+		if _, ok := seen[profile.Name]; ok {
+			return fmt.Errorf("package profile %q defined more than once", profile.Name)
+		}
+		seen[profile.Name] = struct{}{}
+
+		if profile.Platform == "" {
+			return fmt.Errorf("package profile %q: platform not set", profile.Name)
+		}
+
+		if profile.Configuration == "" {
+			profile.Configuration = "Development"
+		}
+
+		if profile.IoStore && !profile.Pak {
+			return fmt.Errorf("package profile %q: iostore requires pak", profile.Name)
+		}
+
+		if profile.ArchiveDirectory != "" {
+			archiveDir, err := resolveConfigPath(configPath, profile.ArchiveDirectory)
+			if err != nil {
+				return fmt.Errorf("package profile %q: archive directory: %w", profile.Name, err)
+			}
+			profile.ArchiveDirectory = archiveDir
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"fmt"
+)
+
+const (
+	// kDefaultCopyright is what the Unreal class wizard writes when no notice is configured.
+	kDefaultCopyright = "Fill out your copyright notice in the Description page of Project Settings."
+)
+
+type GunrealScaffoldingConfig struct {
+	// (optional) Copyright notice written at the top of the generated files.
+	Copyright string `yaml:"copyright"`
+
+	// (optional) Templates for new-class. The first one whose parent matches is used.
+	ClassTemplates []*GunrealClassTemplate `yaml:"class_templates"`
+}
+
+// GunrealClassTemplate points to Go text/template files used to generate new classes.
+// Paths relative to the config file are resolved against it.
+type GunrealClassTemplate struct {
+	// (optional) Parent class this template is for (eg. AActor). Empty matches any parent.
+	Parent string `yaml:"parent"`
+	Header string `yaml:"header"`
+	Source string `yaml:"source"`
+}
+
+// CopyrightNotice returns the configured copyright notice, or the Unreal default.
+func (gsc *GunrealScaffoldingConfig) CopyrightNotice() string {
+	if gsc == nil || gsc.Copyright == "" {
+		return kDefaultCopyright
+	}
+	return gsc.Copyright
+}
+
+// FindClassTemplate returns the template for classes deriving from |parent|, if any.
+func (gsc *GunrealScaffoldingConfig) FindClassTemplate(parent string) *GunrealClassTemplate {
+	if gsc == nil {
+		return nil
+	}
+
+	for _, tmpl := range gsc.ClassTemplates {
+		if tmpl.Parent == "" || tmpl.Parent == parent {
+			return tmpl
+		}
+	}
+
+	return nil
+}
+
+func resolveScaffoldingConfig(configPath string, gsc *GunrealScaffoldingConfig) error {
+	// Scaffolding config is optional.
+	if gsc == nil {
+		return nil
+	}
+
+	for i, tmpl := range gsc.ClassTemplates {
+		header, err := checkFile(configPath, tmpl.Header)
+		if err != nil {
+			return fmt.Errorf("class template %d header: %w", i, err)
+		}
+		tmpl.Header = header
+
+		source, err := checkFile(configPath, tmpl.Source)
+		if err != nil {
+			return fmt.Errorf("class template %d source: %w", i, err)
+		}
+		tmpl.Source = source
+	}
+
+	return nil
+}
@@ -0,0 +1,138 @@
+package unreal
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+)
+
+// AffectedSet is what needs to be rebuilt (and retested) when a set of files changes.
+type AffectedSet struct {
+	// ChangedModules are the modules that own at least one of the changed files.
+	ChangedModules []string `json:"changed_modules"`
+	// Modules are the changed modules plus every module that transitively depends on them.
+	Modules []string `json:"modules"`
+	// Plugins are the plugins that own any of |Modules|.
+	Plugins []string `json:"plugins"`
+	// Targets are the targets that build any of |Modules|.
+	Targets []string `json:"targets"`
+	// Unattributed are the changed files that do not belong to any module (eg. content or config).
+	Unattributed []string `json:"unattributed"`
+}
+
+// AffectedBy calculates what |paths| (absolute) affect by walking the module dependency graph in
+// reverse. Changes to descriptors are taken into account too: a .uplugin affects all the modules of
+// the plugin, a .Target.cs affects its target and the .uproject affects every target.
+func (p *Project) AffectedBy(ctx context.Context, paths []string) (*AffectedSet, error) {
+	if !p.IsIndexed() {
+		if err := p.IndexModules(ctx); err != nil {
+			return nil, fmt.Errorf("indexing modules: %w", err)
+		}
+	}
+
+	pluginDescriptors := map[string]*Plugin{}
+	for _, plugin := range p.Plugins {
+		pluginDescriptors[filepath.Clean(plugin.DescriptorPath)] = plugin
+	}
+	targetFiles := map[string]*Target{}
+	for _, target := range p.Targets {
+		targetFiles[filepath.Clean(target.Path)] = target
+	}
+
+	changed := map[string]struct{}{}
+	affectedTargets := map[string]struct{}{}
+	set := &AffectedSet{}
+	for _, path := range paths {
+		path = filepath.Clean(path)
+
+		if module, err := p.identifyModule(path); err == nil {
+			changed[module.Name] = struct{}{}
+			continue
+		}
+
+		if plugin, ok := pluginDescriptors[path]; ok {
+			for _, module := range p.Modules {
+				if module.Plugin == plugin {
+					changed[module.Name] = struct{}{}
+				}
+			}
+			continue
+		}
+
+		if target, ok := targetFiles[path]; ok {
+			affectedTargets[target.Name] = struct{}{}
+			continue
+		}
+
+		if p.Config.UProjectPath != "" && path == filepath.Clean(p.Config.UProjectPath) {
+			for name := range p.Targets {
+				affectedTargets[name] = struct{}{}
+			}
+			continue
+		}
+
+		set.Unattributed = append(set.Unattributed, path)
+	}
+	sort.Strings(set.Unattributed)
+
+	// Reverse the dependency graph, so that we can go from a module to the ones that use it.
+	dependents := map[string][]string{}
+	for _, module := range p.Modules {
+		if module.Rules == nil {
+			continue
+		}
+		for _, dep := range module.Rules.AllDependencies() {
+			dependents[dep] = append(dependents[dep], module.Name)
+		}
+	}
+
+	affected := map[string]struct{}{}
+	var queue []string
+	for name := range changed {
+		set.ChangedModules = append(set.ChangedModules, name)
+		queue = append(queue, name)
+	}
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+
+		if _, ok := affected[name]; ok {
+			continue
+		}
+		affected[name] = struct{}{}
+
+		queue = append(queue, dependents[name]...)
+	}
+	sort.Strings(set.ChangedModules)
+
+	plugins := map[string]struct{}{}
+	for name := range affected {
+		set.Modules = append(set.Modules, name)
+		if plugin := p.Modules[name].Plugin; plugin != nil {
+			plugins[plugin.Name] = struct{}{}
+		}
+	}
+	sort.Strings(set.Modules)
+
+	for name := range plugins {
+		set.Plugins = append(set.Plugins, name)
+	}
+	sort.Strings(set.Plugins)
+
+	for _, target := range p.sortedTargets() {
+		if _, ok := affectedTargets[target.Name]; ok {
+			set.Targets = append(set.Targets, target.Name)
+			continue
+		}
+
+		for _, name := range p.TargetModules(target) {
+			if _, ok := affected[name]; ok {
+				set.Targets = append(set.Targets, target.Name)
+				break
+			}
+		}
+	}
+
+	return set, nil
+}
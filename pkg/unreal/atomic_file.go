@@ -0,0 +1,142 @@
+package unreal
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/cristiandonosoc/golib/pkg/files"
+)
+
+// atomicFile is a file that gets written to a temporary location and only replaces the real path
+// once it's committed. This way readers (eg. clangd or a file watcher) never see a partial write.
+type atomicFile struct {
+	path string
+	tmp  *os.File
+}
+
+// newAtomicFile creates the temporary file backing |path|. The temporary file lives in the same
+// directory so that the final rename does not cross filesystems.
+func newAtomicFile(path string) (*atomicFile, error) {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("creating dir %q: %w", dir, err)
+	}
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".tmp*")
+	if err != nil {
+		return nil, fmt.Errorf("creating temp file for %q: %w", path, err)
+	}
+
+	return &atomicFile{
+		path: path,
+		tmp:  tmp,
+	}, nil
+}
+
+func (af *atomicFile) Write(data []byte) (int, error) {
+	return af.tmp.Write(data)
+}
+
+// Commit flushes the temporary file and renames it into the final path.
+// If |backup| is set and a previous version existed, it is kept next to it with a .bak suffix.
+func (af *atomicFile) Commit(backup bool) error {
+	tmpPath := af.tmp.Name()
+
+	if err := af.tmp.Sync(); err != nil {
+		af.Abort()
+		return fmt.Errorf("syncing %q: %w", tmpPath, err)
+	}
+
+	if err := af.tmp.Close(); err != nil {
+		af.Abort()
+		return fmt.Errorf("closing %q: %w", tmpPath, err)
+	}
+
+	// CreateTemp creates files only readable by the user.
+	if err := os.Chmod(tmpPath, 0644); err != nil {
+		af.Abort()
+		return fmt.Errorf("chmod %q: %w", tmpPath, err)
+	}
+
+	if backup {
+		if err := backupFile(af.path); err != nil {
+			af.Abort()
+			return fmt.Errorf("backing up %q: %w", af.path, err)
+		}
+	}
+
+	if err := os.Rename(tmpPath, af.path); err != nil {
+		af.Abort()
+		return fmt.Errorf("renaming %q to %q: %w", tmpPath, af.path, err)
+	}
+
+	return nil
+}
+
+// Abort discards the temporary file, leaving the final path untouched.
+// Safe to call after a failed commit.
+func (af *atomicFile) Abort() {
+	af.tmp.Close()
+	os.Remove(af.tmp.Name())
+}
+
+// writeFileAtomically writes |data| to |path| through an atomicFile.
+func writeFileAtomically(path string, data []byte, backup bool) error {
+	af, err := newAtomicFile(path)
+	if err != nil {
+		return err
+	}
+
+	if _, err := af.Write(data); err != nil {
+		af.Abort()
+		return fmt.Errorf("writing %q: %w", path, err)
+	}
+
+	return af.Commit(backup)
+}
+
+// backupFile keeps the current content of |path|, if it exists, in |path|.bak. The backup is a hard
+// link when possible: the commit then renames the new file over |path|, leaving the link as the only
+// name of the old content, without copying what can be hundreds of MBs. Otherwise the file is
+// streamed into the backup.
+func backupFile(path string) error {
+	if _, found, err := files.StatFile(path); err != nil {
+		return fmt.Errorf("statting %q: %w", path, err)
+	} else if !found {
+		return nil
+	}
+
+	backupPath := path + ".bak"
+	if err := os.Remove(backupPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("removing previous backup %q: %w", backupPath, err)
+	}
+
+	if err := os.Link(path, backupPath); err == nil {
+		return nil
+	}
+
+	return copyFileAtomically(path, backupPath)
+}
+
+// copyFileAtomically streams |src| into |dst| through an atomicFile.
+func copyFileAtomically(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("opening %q: %w", src, err)
+	}
+	defer in.Close()
+
+	af, err := newAtomicFile(dst)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(af, in); err != nil {
+		af.Abort()
+		return fmt.Errorf("copying %q to %q: %w", src, dst, err)
+	}
+
+	return af.Commit(false)
+}
@@ -0,0 +1,334 @@
+package unreal
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// kAutomationReportFile is the file Unreal writes within -ReportExportPath.
+	kAutomationReportFile = "index.json"
+)
+
+// AutomationTestState is the outcome of a single automation test, as Unreal reports it.
+type AutomationTestState string
+
+const (
+	AutomationTestState_Success   AutomationTestState = "Success"
+	AutomationTestState_Fail      AutomationTestState = "Fail"
+	AutomationTestState_NotRun    AutomationTestState = "NotRun"
+	AutomationTestState_InProcess AutomationTestState = "InProcess"
+	AutomationTestState_Skipped   AutomationTestState = "Skipped"
+)
+
+// AutomationReport is the index.json Unreal exports after running automation tests.
+type AutomationReport struct {
+	Succeeded             int               `json:"succeeded"`
+	SucceededWithWarnings int               `json:"succeededWithWarnings"`
+	Failed                int               `json:"failed"`
+	NotRun                int               `json:"notRun"`
+	TotalDuration         float64           `json:"totalDuration"`
+	Tests                 []*AutomationTest `json:"tests"`
+
+	// Path is where the report was loaded from.
+	Path string `json:"-"`
+}
+
+// AutomationTest is a single test within an |AutomationReport|.
+type AutomationTest struct {
+	DisplayName string                 `json:"testDisplayName"`
+	FullPath    string                 `json:"fullTestPath"`
+	State       AutomationTestState    `json:"state"`
+	Duration    float64                `json:"duration"`
+	Warnings    int                    `json:"warnings"`
+	Errors      int                    `json:"errors"`
+	Entries     []*AutomationTestEntry `json:"entries"`
+}
+
+// AutomationTestEntry is an event (log, warning or error) a test recorded.
+type AutomationTestEntry struct {
+	Event struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"event"`
+	Filename   string `json:"filename"`
+	LineNumber int    `json:"lineNumber"`
+}
+
+// Failed returns whether the test did not pass.
+func (at *AutomationTest) Failed() bool {
+	return at.State == AutomationTestState_Fail
+}
+
+// Skipped returns whether the test did not get to run to completion.
+func (at *AutomationTest) Skipped() bool {
+	switch at.State {
+	case AutomationTestState_NotRun, AutomationTestState_InProcess, AutomationTestState_Skipped:
+		return true
+	default:
+		return false
+	}
+}
+
+// ErrorEntries returns the entries of the test that are errors.
+func (at *AutomationTest) ErrorEntries() []*AutomationTestEntry {
+	var result []*AutomationTestEntry
+	for _, entry := range at.Entries {
+		if entry.Event.Type == "Error" {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
+
+// FailedTests returns the tests that did not pass, in report order.
+func (ar *AutomationReport) FailedTests() []*AutomationTest {
+	var result []*AutomationTest
+	for _, test := range ar.Tests {
+		if test.Failed() {
+			result = append(result, test)
+		}
+	}
+	return result
+}
+
+// Summary returns a one line summary of the report (eg. "10 passed, 1 failed, 0 skipped in 12.3s").
+func (ar *AutomationReport) Summary() string {
+	passed, failed, skipped := 0, 0, 0
+	for _, test := range ar.Tests {
+		switch {
+		case test.Failed():
+			failed++
+		case test.Skipped():
+			skipped++
+		default:
+			passed++
+		}
+	}
+
+	return fmt.Sprintf("%d passed, %d failed, %d skipped in %.1fs", passed, failed, skipped, ar.TotalDuration)
+}
+
+// LoadAutomationReport reads the index.json within |reportDir|.
+func LoadAutomationReport(reportDir string) (*AutomationReport, error) {
+	path := filepath.Join(reportDir, kAutomationReportFile)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %q: %w", path, err)
+	}
+
+	// Unreal writes the report with a BOM.
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	report := &AutomationReport{Path: path}
+	if err := json.Unmarshal(data, report); err != nil {
+		return nil, fmt.Errorf("unmarshalling %q: %w", path, err)
+	}
+
+	return report, nil
+}
+
+// AutomationTestOptions describes how |RunAutomationTests| runs the tests.
+type AutomationTestOptions struct {
+	// Filter selects the tests to run (eg. Project.Gameplay). Empty runs all of them.
+	Filter string
+	// ReportDir is where Unreal exports the report to.
+	ReportDir string
+	// ExtraArgs are appended at the end of the editor command line.
+	ExtraArgs []string
+}
+
+// AutomationTestArgs returns the command line (executable first) |RunAutomationTests| would use.
+// The editor runs headless (no rendering and no dialogs), so that it works on CI machines.
+func (p *Project) AutomationTestArgs(options *AutomationTestOptions) ([]string, error) {
+	if p.Config.UProjectPath == "" {
+		return nil, fmt.Errorf("no uproject configured to run tests for")
+	}
+
+	executable, err := p.EditorExecutable(true)
+	if err != nil {
+		return nil, err
+	}
+
+	command := "Automation RunAll"
+	if options.Filter != "" {
+		command = "Automation RunTests " + options.Filter
+	}
+
+	args := []string{
+		executable,
+		p.Config.UProjectPath,
+		"-ExecCmds=" + command + ";Quit",
+		"-ReportExportPath=" + options.ReportDir,
+		"-nullrhi",
+		"-unattended",
+		"-nopause",
+		"-nosplash",
+		"-nosound",
+		"-stdout",
+	}
+	args = append(args, options.ExtraArgs...)
+
+	return args, nil
+}
+
+// RunAutomationTests runs the automation tests in a headless editor and loads the report it exports.
+// A failing editor is only an error if it did not manage to export a report.
+func (p *Project) RunAutomationTests(options *AutomationTestOptions) (*AutomationReport, error) {
+	args, err := p.AutomationTestArgs(options)
+	if err != nil {
+		return nil, err
+	}
+
+	// Make sure we do not pick up the report of a previous run.
+	reportPath := filepath.Join(options.ReportDir, kAutomationReportFile)
+	if err := os.Remove(reportPath); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("removing previous report %q: %w", reportPath, err)
+	}
+	if err := os.MkdirAll(options.ReportDir, 0755); err != nil {
+		return nil, fmt.Errorf("creating report dir %q: %w", options.ReportDir, err)
+	}
+
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Dir = p.ProjectDir()
+	setUnrealCommandLine(cmd)
+
+	fmt.Println("> Running:", cmd.Args)
+
+	runErr := runForwardingSignals(cmd)
+
+	report, err := LoadAutomationReport(options.ReportDir)
+	if err != nil {
+		if runErr != nil {
+			return nil, fmt.Errorf("running tests: %w", runErr)
+		}
+		return nil, fmt.Errorf("loading report: %w", err)
+	}
+
+	return report, nil
+}
+
+// junitTestSuites is the root of a JUnit XML report.
+type junitTestSuites struct {
+	XMLName  xml.Name          `xml:"testsuites"`
+	Name     string            `xml:"name,attr"`
+	Tests    int               `xml:"tests,attr"`
+	Failures int               `xml:"failures,attr"`
+	Skipped  int               `xml:"skipped,attr"`
+	Time     float64           `xml:"time,attr"`
+	Suites   []*junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string           `xml:"name,attr"`
+	Tests     int              `xml:"tests,attr"`
+	Failures  int              `xml:"failures,attr"`
+	Skipped   int              `xml:"skipped,attr"`
+	Time      float64          `xml:"time,attr"`
+	TestCases []*junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Time      float64       `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *struct{}     `xml:"skipped,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// JUnit converts the report into JUnit XML, so that CI systems can display it. Tests are grouped in
+// suites by their path without the last component (eg. Project.Gameplay.Jump goes in
+// Project.Gameplay).
+func (ar *AutomationReport) JUnit(name string) ([]byte, error) {
+	root := &junitTestSuites{
+		Name: name,
+		Time: ar.TotalDuration,
+	}
+
+	suites := map[string]*junitTestSuite{}
+	for _, test := range ar.Tests {
+		className, testName := splitAutomationTestPath(test)
+
+		suite, ok := suites[className]
+		if !ok {
+			suite = &junitTestSuite{Name: className}
+			suites[className] = suite
+			root.Suites = append(root.Suites, suite)
+		}
+
+		testCase := &junitTestCase{
+			ClassName: className,
+			Name:      testName,
+			Time:      test.Duration,
+		}
+
+		switch {
+		case test.Failed():
+			message := "test failed"
+			var lines []string
+			for i, entry := range test.ErrorEntries() {
+				if i == 0 {
+					message = entry.Event.Message
+				}
+				lines = append(lines, entry.String())
+			}
+
+			testCase.Failure = &junitFailure{Message: message, Text: strings.Join(lines, "\n")}
+			suite.Failures++
+			root.Failures++
+		case test.Skipped():
+			testCase.Skipped = &struct{}{}
+			suite.Skipped++
+			root.Skipped++
+		}
+
+		suite.TestCases = append(suite.TestCases, testCase)
+		suite.Tests++
+		suite.Time += test.Duration
+		root.Tests++
+	}
+
+	data, err := xml.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshalling junit xml: %w", err)
+	}
+
+	return append([]byte(xml.Header), append(data, '\n')...), nil
+}
+
+// splitAutomationTestPath returns the class name (the path without the last component) and the test
+// name of |test|.
+func splitAutomationTestPath(test *AutomationTest) (string, string) {
+	path := test.FullPath
+	if path == "" {
+		path = test.DisplayName
+	}
+
+	index := strings.LastIndexByte(path, '.')
+	if index < 0 {
+		return path, path
+	}
+	return path[:index], path[index+1:]
+}
+
+// String returns |ate| as "file:line: message", or only the message if there is no location.
+func (ate *AutomationTestEntry) String() string {
+	if ate.Filename == "" {
+		return ate.Event.Message
+	}
+	return fmt.Sprintf("%s:%d: %s", ate.Filename, ate.LineNumber, ate.Event.Message)
+}
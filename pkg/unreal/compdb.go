@@ -1,6 +1,7 @@
 package unreal
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -8,6 +9,10 @@ import (
 	"strings"
 )
 
+const (
+	kCompdbFilename = "compile_commands.json"
+)
+
 var (
 	gCompdb_ubtArgs = []string{
 		"-ProjectFiles",
@@ -69,40 +74,126 @@ type compdbEntry struct {
 	Directory string   `json:"directory"`
 }
 
-func (p *Project) GenerateCompDB() error {
-	// Use UBT to generate the VSCode compilation database.
-	if err := p.UBT(gCompdb_ubtArgs); err != nil {
-		return fmt.Errorf("generating project files: %w", err)
+// CompDBOptions are the knobs that control how the compilation database gets generated.
+type CompDBOptions struct {
+	// Style is the syntax the arguments are written in. Defaults to the cl.exe syntax UBT uses.
+	Style CompDBStyle
+
+	// Force makes the generation ignore the recorded state and always re-run UBT.
+	Force bool
+
+	// Backup keeps the previous compile_commands.json as compile_commands.json.bak.
+	Backup bool
+
+	// Workers is how many entries get rewritten concurrently. Defaults to kCompdbRewriterWorkerCount.
+	Workers int
+}
+
+// CompDBPath is where the generated compilation database lives.
+func (p *Project) CompDBPath() string {
+	return filepath.Join(p.ProjectDir(), kCompdbFilename)
+}
+
+// GenerateCompDB generates the compile_commands.json for the project.
+// Running UBT is expensive, so the state of the build files is recorded in the gunreal dir and UBT
+// is only invoked when any of those changed. If only the set of source files changed, the existing
+// database gets patched instead.
+func (p *Project) GenerateCompDB(ctx context.Context, options *CompDBOptions) error {
+	if options == nil {
+		options = &CompDBOptions{}
 	}
 
-	entries, err := readCompdbEntries(p.Config.ProjectDir, p.Config.ProjectName)
+	if !p.IsIndexed() {
+		if err := p.IndexModules(ctx); err != nil {
+			return fmt.Errorf("indexing modules: %w", err)
+		}
+	}
+
+	state, err := p.collectCompdbState(options.Style)
 	if err != nil {
-		return fmt.Errorf("reading compdb entries: %w", err)
+		return fmt.Errorf("collecting compdb state: %w", err)
+	}
+
+	if !options.Force {
+		done, err := p.updateCompdbIncrementally(ctx, state, options)
+		if err != nil {
+			return fmt.Errorf("updating compdb incrementally: %w", err)
+		}
+
+		if done {
+			return nil
+		}
+	}
+
+	if err := p.regenerateCompdb(ctx, options); err != nil {
+		return err
+	}
+
+	if err := state.save(p.compdbStatePath()); err != nil {
+		return fmt.Errorf("saving compdb state: %w", err)
+	}
+
+	return nil
+}
+
+// regenerateCompdb runs UBT and rewrites the whole compilation database.
+func (p *Project) regenerateCompdb(ctx context.Context, options *CompDBOptions) error {
+	// Use UBT to generate the VSCode compilation database.
+	if err := p.UBT(gCompdb_ubtArgs); err != nil {
+		return fmt.Errorf("generating project files: %w", err)
 	}
-	fmt.Printf("Read %d entries\n", len(entries))
 
 	// Rewrite the flags.
-	compdbDir := filepath.Join(p.Config.ProjectDir, ".gunreal")
+	compdbDir := p.GunrealDir()
 	if err := os.MkdirAll(compdbDir, 0644); err != nil {
 		return fmt.Errorf("creating dir %q: %w", compdbDir, err)
 	}
 
-	rspPath, err := writeExtraFlagsRsp(compdbDir)
+	var translator *gnuTranslator
+	extraFlags := strings.TrimSpace(gExtraClangFlagsRsp)
+	if options.Style == CompDBStyle_GNU {
+		translator = newGNUTranslator(filepath.Join(compdbDir, "gnu_rsp"))
+		extraFlags = joinRspArgs(translateRspArgs(splitRspArgs(extraFlags)))
+	}
+
+	rspPath, err := writeExtraFlagsRsp(compdbDir, extraFlags)
 	if err != nil {
 		return fmt.Errorf("writing extra clang flags rsp file: %w", err)
 	}
+	rspArgument := fmt.Sprintf("@%s", rspPath)
+
+	// The UBT database can be hundreds of MB, so we rewrite it one entry at a time.
+	streamOptions := &compdbStreamOptions{
+		Workers: options.Workers,
+		Backup:  options.Backup,
+		Transform: func(entry *compdbEntry) (bool, error) {
+			if translator != nil {
+				if err := translator.TranslateEntry(entry); err != nil {
+					return false, fmt.Errorf("translating entry to %s style: %w", options.Style, err)
+				}
+			}
+
+			entry.Arguments = append(entry.Arguments, rspArgument)
+			return true, nil
+		},
+	}
 
-	if err := writeOutCompdb(p.Config.ProjectDir, rspPath, entries); err != nil {
+	count, err := streamCompdb(ctx, p.vscodeCompdbPath(), p.CompDBPath(), streamOptions)
+	if err != nil {
 		return fmt.Errorf("writing out compdb: %w", err)
 	}
 
+	fmt.Printf("Wrote %d entries to %s\n", count, p.CompDBPath())
 	return nil
 }
 
-func readCompdbEntries(projectDir, projectName string) ([]*compdbEntry, error) {
-	compdbName := fmt.Sprintf("compileCommands_%s.json", projectName)
-	compdbPath := filepath.Join(projectDir, ".vscode", compdbName)
+// vscodeCompdbPath is where UBT writes the compilation database when generating VSCode projects.
+func (p *Project) vscodeCompdbPath() string {
+	compdbName := fmt.Sprintf("compileCommands_%s.json", p.Config.ProjectName)
+	return filepath.Join(p.ProjectDir(), ".vscode", compdbName)
+}
 
+func readCompdbFile(compdbPath string) ([]*compdbEntry, error) {
 	data, err := os.ReadFile(compdbPath)
 	if err != nil {
 		return nil, fmt.Errorf("reading %q: %w", compdbPath, err)
@@ -116,44 +207,12 @@ func readCompdbEntries(projectDir, projectName string) ([]*compdbEntry, error) {
 	return entries, nil
 }
 
-func writeExtraFlagsRsp(dir string) (string, error) {
+func writeExtraFlagsRsp(dir, flags string) (string, error) {
 	rspPath := filepath.Join(dir, "extra_clang_flags.rsp")
 
-	file, err := os.OpenFile(rspPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
-	if err != nil {
-		return "", fmt.Errorf("opening %q: %w", rspPath, err)
-	}
-	defer file.Close()
-
-	if _, err := file.WriteString(strings.TrimSpace(gExtraClangFlagsRsp)); err != nil {
+	if err := writeFileAtomically(rspPath, []byte(flags), false); err != nil {
 		return "", fmt.Errorf("writing extra clang flags: %w", err)
 	}
 
 	return rspPath, nil
 }
-
-func writeOutCompdb(projectDir, rspFilePath string, entries []*compdbEntry) error {
-	rspArgument := fmt.Sprintf("@%s", rspFilePath)
-	for _, entry := range entries {
-		entry.Arguments = append(entry.Arguments, rspArgument)
-	}
-
-	content, err := json.MarshalIndent(entries, "", "  ")
-	if err != nil {
-		return fmt.Errorf("marshalling comdb entries: %w", err)
-	}
-
-	compdbPath := filepath.Join(projectDir, "compile_commands.json")
-	file, err := os.OpenFile(compdbPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
-	if err != nil {
-		return fmt.Errorf("opening %q: %w", compdbPath, err)
-	}
-	defer file.Close()
-
-	if _, err := file.Write(content); err != nil {
-		return fmt.Errorf("writing compdb entries: %w", err)
-	}
-
-	fmt.Printf("Wrote compilation database to %s\n", compdbPath)
-	return nil
-}
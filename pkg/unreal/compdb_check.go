@@ -0,0 +1,387 @@
+package unreal
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cristiandonosoc/golib/pkg/files"
+)
+
+// CompDBIssueKind identifies the type of problem found within a compilation database.
+type CompDBIssueKind string
+
+const (
+	CompDBIssue_MissingFile       CompDBIssueKind = "missing-file"
+	CompDBIssue_MissingEntry      CompDBIssueKind = "missing-entry"
+	CompDBIssue_DuplicateEntry    CompDBIssueKind = "duplicate-entry"
+	CompDBIssue_MissingIncludeDir CompDBIssueKind = "missing-include-dir"
+	CompDBIssue_MissingRspFile    CompDBIssueKind = "missing-rsp-file"
+)
+
+// CompDBIssue is a single problem found while checking a compilation database.
+type CompDBIssue struct {
+	Kind CompDBIssueKind
+	// Path is the file or directory the issue is about.
+	Path string
+	// Detail is an optional human readable explanation.
+	Detail string
+}
+
+func (ci *CompDBIssue) String() string {
+	if ci.Detail == "" {
+		return fmt.Sprintf("[%s] %s", ci.Kind, ci.Path)
+	}
+	return fmt.Sprintf("[%s] %s (%s)", ci.Kind, ci.Path, ci.Detail)
+}
+
+// CheckCompDB loads the existing compilation database and validates it against the disk and the
+// indexed modules. Returns the issues sorted by kind and path.
+func (p *Project) CheckCompDB(ctx context.Context) ([]*CompDBIssue, error) {
+	if !p.IsIndexed() {
+		if err := p.IndexModules(ctx); err != nil {
+			return nil, fmt.Errorf("indexing modules: %w", err)
+		}
+	}
+
+	entries, err := readCompdbFile(p.CompDBPath())
+	if err != nil {
+		return nil, fmt.Errorf("reading compdb: %w", err)
+	}
+
+	var issues []*CompDBIssue
+
+	// Check the entries themselves.
+	seen := map[string]int{}
+	for _, entry := range entries {
+		path := compdbEntryPath(entry)
+		seen[path]++
+
+		if seen[path] == 2 {
+			issues = append(issues, &CompDBIssue{
+				Kind: CompDBIssue_DuplicateEntry,
+				Path: path,
+			})
+		}
+
+		if seen[path] > 1 {
+			continue
+		}
+
+		if _, found, err := files.StatFile(path); err != nil {
+			return nil, fmt.Errorf("statting %q: %w", path, err)
+		} else if !found {
+			issues = append(issues, &CompDBIssue{
+				Kind: CompDBIssue_MissingFile,
+				Path: path,
+			})
+		}
+	}
+
+	// Check that every source file in the indexed modules has an entry.
+	for _, module := range p.Modules {
+		for _, file := range module.Files {
+			if !hasAnySuffix(strings.ToLower(file), gCompdbSourceExtensions) {
+				continue
+			}
+
+			if _, ok := seen[filepath.Clean(file)]; !ok {
+				issues = append(issues, &CompDBIssue{
+					Kind:   CompDBIssue_MissingEntry,
+					Path:   file,
+					Detail: fmt.Sprintf("module %s", module.Name),
+				})
+			}
+		}
+	}
+
+	// Check the include directories. Many entries share them, so we only stat each one once.
+	rsps := newRspCache()
+	includeDirs := map[string]string{}
+	for _, entry := range entries {
+		args, missing, err := rsps.expandArgs(entry.Arguments)
+		if err != nil {
+			return nil, fmt.Errorf("expanding arguments for %q: %w", entry.File, err)
+		}
+
+		// The rest of the entry can still be checked without the missing response files.
+		for _, rspPath := range missing {
+			issues = append(issues, &CompDBIssue{
+				Kind:   CompDBIssue_MissingRspFile,
+				Path:   rspPath,
+				Detail: fmt.Sprintf("used by %s", entry.File),
+			})
+		}
+
+		for _, dir := range extractIncludeDirs(args) {
+			if !filepath.IsAbs(dir) {
+				dir = filepath.Join(entry.Directory, dir)
+			}
+			dir = filepath.Clean(dir)
+
+			if _, ok := includeDirs[dir]; !ok {
+				includeDirs[dir] = entry.File
+			}
+		}
+	}
+
+	for dir, firstUser := range includeDirs {
+		exists, err := files.DirExists(dir)
+		if err != nil {
+			return nil, fmt.Errorf("checking include dir %q: %w", dir, err)
+		}
+
+		if !exists {
+			issues = append(issues, &CompDBIssue{
+				Kind:   CompDBIssue_MissingIncludeDir,
+				Path:   dir,
+				Detail: fmt.Sprintf("used by %s", firstUser),
+			})
+		}
+	}
+
+	sort.Slice(issues, func(i, j int) bool {
+		if issues[i].Kind != issues[j].Kind {
+			return issues[i].Kind < issues[j].Kind
+		}
+		return issues[i].Path < issues[j].Path
+	})
+
+	return issues, nil
+}
+
+// rspCache holds the response files read while expanding the arguments of many entries, which
+// usually share them.
+type rspCache struct {
+	args map[string][]string
+	// missing are the response files that do not exist. They are only reported the first time.
+	missing map[string]struct{}
+}
+
+func newRspCache() *rspCache {
+	return &rspCache{
+		args:    map[string][]string{},
+		missing: map[string]struct{}{},
+	}
+}
+
+// expandArgs replaces the response file arguments with their contents. Response files that do not
+// exist are left as they are and returned in |missing| the first time they are found.
+func (rc *rspCache) expandArgs(args []string) (result []string, missing []string, err error) {
+	for _, arg := range args {
+		rspPath, ok := strings.CutPrefix(arg, "@")
+		if !ok {
+			result = append(result, arg)
+			continue
+		}
+
+		if _, ok := rc.missing[rspPath]; ok {
+			result = append(result, arg)
+			continue
+		}
+
+		rspArgs, ok := rc.args[rspPath]
+		if !ok {
+			data, err := os.ReadFile(rspPath)
+			if err != nil {
+				if os.IsNotExist(err) {
+					rc.missing[rspPath] = struct{}{}
+					missing = append(missing, rspPath)
+					result = append(result, arg)
+					continue
+				}
+				return nil, nil, fmt.Errorf("reading %q: %w", rspPath, err)
+			}
+
+			rspArgs = splitRspArgs(string(data))
+			rc.args[rspPath] = rspArgs
+		}
+
+		result = append(result, rspArgs...)
+	}
+
+	return result, missing, nil
+}
+
+// extractIncludeDirs returns the include directories given in |args|, in either cl.exe or clang
+// syntax.
+func extractIncludeDirs(args []string) []string {
+	var dirs []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		for _, prefix := range []string{"/external:I", "/imsvc", "-isystem", "/I", "-I"} {
+			value, ok := strings.CutPrefix(arg, prefix)
+			if !ok {
+				continue
+			}
+
+			if value == "" && i+1 < len(args) {
+				i++
+				value = args[i]
+			}
+
+			if value != "" {
+				dirs = append(dirs, value)
+			}
+			break
+		}
+	}
+
+	return dirs
+}
+
+// CompDBEntryChange describes how the entry of a single file changed between two databases.
+type CompDBEntryChange struct {
+	File             string
+	AddedArgs        []string
+	RemovedArgs      []string
+	DirectoryChanged bool
+}
+
+// CompDBDiff is the difference between two compilation databases.
+type CompDBDiff struct {
+	Added   []string
+	Removed []string
+	Changed []*CompDBEntryChange
+}
+
+// IsEmpty returns whether both databases were equivalent.
+func (cd *CompDBDiff) IsEmpty() bool {
+	return len(cd.Added) == 0 && len(cd.Removed) == 0 && len(cd.Changed) == 0
+}
+
+// DiffCompDBs explains what changed between the compilation databases at |oldPath| and |newPath|.
+// Arguments are compared as sets, as their order rarely matters for tooling. Response files are
+// expanded first, so that changes within them show up too (missing ones are compared by path).
+func DiffCompDBs(oldPath, newPath string) (*CompDBDiff, error) {
+	oldEntries, err := readCompdbFile(oldPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading old compdb: %w", err)
+	}
+
+	newEntries, err := readCompdbFile(newPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading new compdb: %w", err)
+	}
+
+	oldByFile := indexCompdbEntries(oldEntries)
+	newByFile := indexCompdbEntries(newEntries)
+
+	rsps := newRspCache()
+	diff := &CompDBDiff{}
+	for file, newEntry := range newByFile {
+		oldEntry, ok := oldByFile[file]
+		if !ok {
+			diff.Added = append(diff.Added, file)
+			continue
+		}
+
+		oldArgs, _, err := rsps.expandArgs(oldEntry.Arguments)
+		if err != nil {
+			return nil, fmt.Errorf("expanding old arguments for %q: %w", file, err)
+		}
+		newArgs, _, err := rsps.expandArgs(newEntry.Arguments)
+		if err != nil {
+			return nil, fmt.Errorf("expanding new arguments for %q: %w", file, err)
+		}
+
+		change := &CompDBEntryChange{
+			File:             file,
+			AddedArgs:        argsDifference(newArgs, oldArgs),
+			RemovedArgs:      argsDifference(oldArgs, newArgs),
+			DirectoryChanged: filepath.Clean(oldEntry.Directory) != filepath.Clean(newEntry.Directory),
+		}
+
+		if len(change.AddedArgs) > 0 || len(change.RemovedArgs) > 0 || change.DirectoryChanged {
+			diff.Changed = append(diff.Changed, change)
+		}
+	}
+
+	for file := range oldByFile {
+		if _, ok := newByFile[file]; !ok {
+			diff.Removed = append(diff.Removed, file)
+		}
+	}
+
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Slice(diff.Changed, func(i, j int) bool {
+		return diff.Changed[i].File < diff.Changed[j].File
+	})
+
+	return diff, nil
+}
+
+func (cd *CompDBDiff) Describe() string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("ADDED ENTRIES: %d\n", len(cd.Added)))
+	for _, file := range cd.Added {
+		sb.WriteString(fmt.Sprintf("+ %s\n", file))
+	}
+
+	sb.WriteString(fmt.Sprintf("\nREMOVED ENTRIES: %d\n", len(cd.Removed)))
+	for _, file := range cd.Removed {
+		sb.WriteString(fmt.Sprintf("- %s\n", file))
+	}
+
+	sb.WriteString(fmt.Sprintf("\nCHANGED ENTRIES: %d\n", len(cd.Changed)))
+	for _, change := range cd.Changed {
+		sb.WriteString(fmt.Sprintf("~ %s\n", change.File))
+		if change.DirectoryChanged {
+			sb.WriteString("  - directory changed\n")
+		}
+		for _, arg := range change.AddedArgs {
+			sb.WriteString(fmt.Sprintf("  + %s\n", arg))
+		}
+		for _, arg := range change.RemovedArgs {
+			sb.WriteString(fmt.Sprintf("  - %s\n", arg))
+		}
+	}
+
+	return sb.String()
+}
+
+// indexCompdbEntries maps the entries by their file. Duplicates keep the first entry.
+func indexCompdbEntries(entries []*compdbEntry) map[string]*compdbEntry {
+	result := make(map[string]*compdbEntry, len(entries))
+	for _, entry := range entries {
+		path := compdbEntryPath(entry)
+		if _, ok := result[path]; !ok {
+			result[path] = entry
+		}
+	}
+
+	return result
+}
+
+// compdbEntryPath returns the file of |entry|. Relative files are resolved against the directory of
+// the entry, as the compilation database format allows them.
+func compdbEntryPath(entry *compdbEntry) string {
+	path := entry.File
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(entry.Directory, path)
+	}
+	return filepath.Clean(path)
+}
+
+// argsDifference returns the arguments in |a| that are not in |b|.
+func argsDifference(a, b []string) []string {
+	set := make(map[string]struct{}, len(b))
+	for _, arg := range b {
+		set[arg] = struct{}{}
+	}
+
+	var result []string
+	for _, arg := range a {
+		if _, ok := set[arg]; !ok {
+			result = append(result, arg)
+		}
+	}
+
+	return result
+}
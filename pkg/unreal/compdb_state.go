@@ -0,0 +1,314 @@
+package unreal
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cristiandonosoc/golib/pkg/files"
+)
+
+// gCompdbTrackedSuffixes are the (lowercase) suffixes of the files that, when changed, require UBT
+// to regenerate the compilation database.
+var gCompdbTrackedSuffixes = []string{
+	".build.cs",
+	".target.cs",
+	".uplugin",
+	".uproject",
+}
+
+// gCompdbSourceExtensions are the files that get an entry in the compilation database.
+var gCompdbSourceExtensions = []string{
+	".cpp",
+}
+
+// compdbState is a snapshot of everything that affects the contents of the compilation database.
+// It is stored in the gunreal dir so that the next generation can know what changed.
+type compdbState struct {
+	Style CompDBStyle `json:"style"`
+
+	// BuildFiles maps build file paths to the sha256 of their contents.
+	BuildFiles map[string]string `json:"build_files"`
+
+	// ModuleSources maps module names to the sorted list of their source files.
+	ModuleSources map[string][]string `json:"module_sources"`
+}
+
+func (p *Project) compdbStatePath() string {
+	return filepath.Join(p.GunrealDir(), "compdb_state.json")
+}
+
+// collectCompdbState snapshots the current state of the (indexed) project.
+func (p *Project) collectCompdbState(style CompDBStyle) (*compdbState, error) {
+	state := &compdbState{
+		Style:         style,
+		BuildFiles:    map[string]string{},
+		ModuleSources: map[string][]string{},
+	}
+
+	// Collect the build files. Target and plugin files are not part of any module, so we need to walk
+	// the directories that could hold them.
+	var buildFiles []string
+	if p.Config.UProjectPath != "" {
+		buildFiles = append(buildFiles, p.Config.UProjectPath)
+	}
+
+	for _, dir := range []string{p.SourceDir(), filepath.Join(p.ProjectDir(), "Plugins")} {
+		found, err := findCompdbTrackedFiles(dir)
+		if err != nil {
+			return nil, fmt.Errorf("searching build files in %q: %w", dir, err)
+		}
+		buildFiles = append(buildFiles, found...)
+	}
+
+	for _, buildFile := range buildFiles {
+		hash, err := hashFile(buildFile)
+		if err != nil {
+			return nil, fmt.Errorf("hashing %q: %w", buildFile, err)
+		}
+		state.BuildFiles[buildFile] = hash
+	}
+
+	// Collect the source files of each module.
+	for _, module := range p.Modules {
+		var sources []string
+		for _, file := range module.Files {
+			if hasAnySuffix(strings.ToLower(file), gCompdbSourceExtensions) {
+				sources = append(sources, file)
+			}
+		}
+		sort.Strings(sources)
+
+		state.ModuleSources[module.Name] = sources
+	}
+
+	return state, nil
+}
+
+// findCompdbTrackedFiles walks |dir| searching for the files that affect UBT's output.
+// A non-existent |dir| is not an error.
+func findCompdbTrackedFiles(dir string) ([]string, error) {
+	var result []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return fmt.Errorf("path %q: %w", path, err)
+		}
+
+		if d.IsDir() {
+			// Generated directories can be huge and never hold build files we care about.
+			switch d.Name() {
+			case "Intermediate", "Binaries", "Saved":
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if hasAnySuffix(strings.ToLower(path), gCompdbTrackedSuffixes) {
+			result = append(result, path)
+		}
+
+		return nil
+	})
+
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("walking %q: %w", dir, err)
+	}
+
+	return result, nil
+}
+
+// loadCompdbState loads a previously stored state. Returns false if there was none.
+func loadCompdbState(path string) (*compdbState, bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("reading %q: %w", path, err)
+	}
+
+	state := &compdbState{}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, false, fmt.Errorf("unmarshalling compdb state: %w", err)
+	}
+
+	return state, true, nil
+}
+
+func (cs *compdbState) save(path string) error {
+	data, err := json.MarshalIndent(cs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshalling compdb state: %w", err)
+	}
+
+	if err := writeFileAtomically(path, data, false); err != nil {
+		return fmt.Errorf("writing %q: %w", path, err)
+	}
+
+	return nil
+}
+
+// updateCompdbIncrementally attempts to bring the existing compilation database up to date without
+// running UBT. Returns false if that is not possible and a full regeneration is needed.
+func (p *Project) updateCompdbIncrementally(ctx context.Context, state *compdbState, options *CompDBOptions) (bool, error) {
+	prev, found, err := loadCompdbState(p.compdbStatePath())
+	if err != nil {
+		return false, fmt.Errorf("loading previous compdb state: %w", err)
+	}
+
+	if !found {
+		fmt.Println("No previous compdb state found. Doing full regeneration.")
+		return false, nil
+	}
+
+	if prev.Style != state.Style {
+		fmt.Printf("Compdb style changed (%q -> %q). Doing full regeneration.\n", prev.Style, state.Style)
+		return false, nil
+	}
+
+	if !maps.Equal(prev.BuildFiles, state.BuildFiles) {
+		fmt.Println("Build files changed. Doing full regeneration.")
+		return false, nil
+	}
+
+	if _, found, err := files.StatFile(p.CompDBPath()); err != nil {
+		return false, fmt.Errorf("statting %q: %w", p.CompDBPath(), err)
+	} else if !found {
+		fmt.Printf("%q not found. Doing full regeneration.\n", p.CompDBPath())
+		return false, nil
+	}
+
+	// Calculate which sources were added and removed.
+	removed := map[string]struct{}{}
+	added := map[string][]string{}
+	for moduleName, sources := range state.ModuleSources {
+		prevSources := prev.ModuleSources[moduleName]
+
+		for _, source := range sources {
+			if !containsSorted(prevSources, source) {
+				added[moduleName] = append(added[moduleName], source)
+			}
+		}
+
+		for _, source := range prevSources {
+			if !containsSorted(sources, source) {
+				removed[filepath.Clean(source)] = struct{}{}
+			}
+		}
+	}
+
+	if len(added) == 0 && len(removed) == 0 {
+		fmt.Println("Compilation database is up to date.")
+		return true, nil
+	}
+
+	// Added files get their entry from another file within the same module, as all the files within
+	// a module are compiled with the same arguments. We find those in a first read-only pass.
+	templateModules := map[string]string{}
+	for moduleName := range added {
+		for _, source := range prev.ModuleSources[moduleName] {
+			templateModules[filepath.Clean(source)] = moduleName
+		}
+	}
+
+	templates := map[string]*compdbEntry{}
+	err = visitCompdbEntries(ctx, p.CompDBPath(), func(entry *compdbEntry) error {
+		path := filepath.Clean(entry.File)
+		if _, ok := removed[path]; ok {
+			return nil
+		}
+
+		if moduleName, ok := templateModules[path]; ok {
+			if _, ok := templates[moduleName]; !ok {
+				templates[moduleName] = entry
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("searching template entries: %w", err)
+	}
+
+	var extra []*compdbEntry
+	for moduleName, sources := range added {
+		template, ok := templates[moduleName]
+		if !ok {
+			fmt.Printf("No entry to base new files of module %q on. Doing full regeneration.\n", moduleName)
+			return false, nil
+		}
+
+		for _, source := range sources {
+			extra = append(extra, cloneCompdbEntry(template, source))
+		}
+	}
+
+	// Now we rewrite the database removing the entries for deleted files.
+	streamOptions := &compdbStreamOptions{
+		Workers: options.Workers,
+		Backup:  options.Backup,
+		Transform: func(entry *compdbEntry) (bool, error) {
+			_, ok := removed[filepath.Clean(entry.File)]
+			return !ok, nil
+		},
+		Extra: extra,
+	}
+
+	fmt.Printf("Patching compdb: %d files removed, %d added\n", len(removed), len(extra))
+	if _, err := streamCompdb(ctx, p.CompDBPath(), p.CompDBPath(), streamOptions); err != nil {
+		return false, fmt.Errorf("writing patched compdb: %w", err)
+	}
+
+	if err := state.save(p.compdbStatePath()); err != nil {
+		return false, fmt.Errorf("saving compdb state: %w", err)
+	}
+
+	return true, nil
+}
+
+// cloneCompdbEntry creates a new entry for |file| with the same arguments as |template|.
+func cloneCompdbEntry(template *compdbEntry, file string) *compdbEntry {
+	args := make([]string, len(template.Arguments))
+	for i, arg := range template.Arguments {
+		args[i] = strings.ReplaceAll(arg, template.File, file)
+	}
+
+	return &compdbEntry{
+		File:      file,
+		Arguments: args,
+		Directory: template.Directory,
+	}
+}
+
+func containsSorted(list []string, value string) bool {
+	index := sort.SearchStrings(list, value)
+	return index < len(list) && list[index] == value
+}
+
+func hasAnySuffix(s string, suffixes []string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(s, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func hashFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("reading %q: %w", path, err)
+	}
+
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:]), nil
+}
@@ -0,0 +1,262 @@
+package unreal
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	kCompdbRewriterWorkerCount = 16
+	kCompdbStreamBufferSize    = 1 << 20
+)
+
+// compdbStreamOptions configures a streaming rewrite of a compilation database.
+type compdbStreamOptions struct {
+	// Workers is how many entries are rewritten concurrently. Defaults to kCompdbRewriterWorkerCount.
+	Workers int
+
+	// Backup keeps the previous version of the output with a .bak suffix.
+	Backup bool
+
+	// Transform is called on each entry (possibly concurrently). Returning false drops the entry.
+	// Can be nil, in which case the entries are copied as is.
+	Transform func(entry *compdbEntry) (bool, error)
+
+	// Extra are entries appended at the end of the output, untransformed.
+	Extra []*compdbEntry
+}
+
+// visitCompdbEntries decodes the compdb at |path| one entry at a time, without ever holding the
+// whole database in memory.
+func visitCompdbEntries(ctx context.Context, path string, visitor func(entry *compdbEntry) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("opening %q: %w", path, err)
+	}
+	defer file.Close()
+
+	dec := json.NewDecoder(bufio.NewReaderSize(file, kCompdbStreamBufferSize))
+
+	// The compdb is a JSON array.
+	if token, err := dec.Token(); err != nil {
+		return fmt.Errorf("reading start of %q: %w", path, err)
+	} else if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("%q is not a JSON array", path)
+	}
+
+	for dec.More() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		entry := &compdbEntry{}
+		if err := dec.Decode(entry); err != nil {
+			return fmt.Errorf("decoding entry in %q: %w", path, err)
+		}
+
+		if err := visitor(entry); err != nil {
+			return err
+		}
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return fmt.Errorf("reading end of %q: %w", path, err)
+	}
+
+	return nil
+}
+
+// streamCompdb rewrites the compdb at |inPath| into |outPath| entry by entry, fanning the
+// transformation out to workers. The output keeps the order of the input and is written atomically.
+// |inPath| and |outPath| can be the same file. Returns the amount of entries written.
+func streamCompdb(ctx context.Context, inPath, outPath string, options *compdbStreamOptions) (int, error) {
+	workers := options.Workers
+	if workers <= 0 {
+		workers = kCompdbRewriterWorkerCount
+	}
+
+	out, err := newAtomicFile(outPath)
+	if err != nil {
+		return 0, fmt.Errorf("creating output: %w", err)
+	}
+
+	g, ctx := errgroup.WithContext(ctx)
+
+	type sequencedEntry struct {
+		index int
+		entry *compdbEntry
+		keep  bool
+	}
+
+	// Produce: decode the entries one by one.
+	entriesCh := make(chan *sequencedEntry)
+	{
+		g.Go(func() error {
+			defer close(entriesCh)
+
+			index := 0
+			return visitCompdbEntries(ctx, inPath, func(entry *compdbEntry) error {
+				select {
+				case entriesCh <- &sequencedEntry{index: index, entry: entry}:
+					index++
+					return nil
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			})
+		})
+	}
+
+	// Map: transform each entry.
+	transformedCh := make(chan *sequencedEntry)
+	{
+		var wg sync.WaitGroup
+
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			g.Go(func() error {
+				defer wg.Done()
+
+				for se := range entriesCh {
+					se.keep = true
+					if options.Transform != nil {
+						keep, err := options.Transform(se.entry)
+						if err != nil {
+							return fmt.Errorf("transforming entry for %q: %w", se.entry.File, err)
+						}
+						se.keep = keep
+					}
+
+					select {
+					case transformedCh <- se:
+						continue
+					case <-ctx.Done():
+						return ctx.Err()
+					}
+				}
+
+				return nil
+			})
+		}
+
+		// Make sure the channel will be closed.
+		g.Go(func() error {
+			wg.Wait()
+			close(transformedCh)
+			return nil
+		})
+	}
+
+	// Reduce: write the entries back in their original order.
+	written := 0
+	{
+		g.Go(func() error {
+			writer := newCompdbWriter(out)
+
+			// Workers can finish out of order, so we hold the early ones until it's their turn.
+			pending := map[int]*sequencedEntry{}
+			next := 0
+			for se := range transformedCh {
+				pending[se.index] = se
+
+				for {
+					ready, ok := pending[next]
+					if !ok {
+						break
+					}
+					delete(pending, next)
+					next++
+
+					if !ready.keep {
+						continue
+					}
+
+					if err := writer.Write(ready.entry); err != nil {
+						return err
+					}
+				}
+			}
+
+			for _, entry := range options.Extra {
+				if err := writer.Write(entry); err != nil {
+					return err
+				}
+			}
+
+			written = writer.count
+			return writer.Close()
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		out.Abort()
+		return 0, err
+	}
+
+	if err := out.Commit(options.Backup); err != nil {
+		return 0, fmt.Errorf("committing %q: %w", outPath, err)
+	}
+
+	return written, nil
+}
+
+// compdbWriter encodes entries as a JSON array one at a time. The output matches what
+// json.MarshalIndent(entries, "", "  ") would generate.
+type compdbWriter struct {
+	w     *bufio.Writer
+	count int
+}
+
+func newCompdbWriter(w io.Writer) *compdbWriter {
+	return &compdbWriter{
+		w: bufio.NewWriterSize(w, kCompdbStreamBufferSize),
+	}
+}
+
+func (cw *compdbWriter) Write(entry *compdbEntry) error {
+	data, err := json.MarshalIndent(entry, "  ", "  ")
+	if err != nil {
+		return fmt.Errorf("marshalling entry for %q: %w", entry.File, err)
+	}
+
+	separator := ",\n  "
+	if cw.count == 0 {
+		separator = "[\n  "
+	}
+
+	if _, err := cw.w.WriteString(separator); err != nil {
+		return fmt.Errorf("writing entry: %w", err)
+	}
+
+	if _, err := cw.w.Write(data); err != nil {
+		return fmt.Errorf("writing entry: %w", err)
+	}
+
+	cw.count++
+	return nil
+}
+
+// Close finishes the JSON array and flushes the output. It does not close the underlying writer.
+func (cw *compdbWriter) Close() error {
+	end := "\n]"
+	if cw.count == 0 {
+		end = "[]"
+	}
+
+	if _, err := cw.w.WriteString(end); err != nil {
+		return fmt.Errorf("writing end of compdb: %w", err)
+	}
+
+	if err := cw.w.Flush(); err != nil {
+		return fmt.Errorf("flushing compdb: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,328 @@
+package unreal
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/cristiandonosoc/golib/pkg/files"
+)
+
+// CompDBStyle determines the syntax the arguments of the compilation database are written in.
+type CompDBStyle string
+
+const (
+	// CompDBStyle_MSVC leaves the arguments as UBT generates them (cl.exe syntax).
+	CompDBStyle_MSVC CompDBStyle = "msvc"
+	// CompDBStyle_GNU rewrites the arguments into clang driver syntax.
+	CompDBStyle_GNU CompDBStyle = "gnu"
+)
+
+// NewCompDBStyle attempts to unify the compdb style from identifiers that might come from the
+// outside.
+func NewCompDBStyle(id string) (CompDBStyle, error) {
+	switch strings.ToLower(id) {
+	case "", "msvc", "cl":
+		return CompDBStyle_MSVC, nil
+	case "gnu", "clang", "gcc":
+		return CompDBStyle_GNU, nil
+	default:
+		return "", fmt.Errorf("unrecognized compdb style %q", id)
+	}
+}
+
+func (cs CompDBStyle) String() string {
+	return string(cs)
+}
+
+// gnuStdVersions maps the /std: values of cl.exe into their -std= equivalent.
+var gnuStdVersions = map[string]string{
+	"c++14":     "c++14",
+	"c++17":     "c++17",
+	"c++20":     "c++20",
+	"c++latest": "c++2b",
+	"c11":       "c11",
+	"c17":       "c17",
+}
+
+// gnuDirectFlags are cl.exe flags that have a direct clang equivalent.
+var gnuDirectFlags = map[string][]string{
+	"/c":    {"-c"},
+	"/TP":   {"-x", "c++"},
+	"/TC":   {"-x", "c"},
+	"/GR-":  {"-fno-rtti"},
+	"/GR":   {"-frtti"},
+	"/EHsc": {"-fexceptions"},
+	"/W0":   {"-w"},
+	"/W1":   {"-Wall"},
+	"/W2":   {"-Wall"},
+	"/W3":   {"-Wall"},
+	"/W4":   {"-Wall", "-Wextra"},
+	"/Wall": {"-Wall", "-Wextra"},
+	"/WX":   {"-Werror"},
+}
+
+// msvcPrefixedFlags are the cl.exe flags that carry a value, either attached (/DFOO) or as the
+// next argument (/D FOO). The order matters: longer prefixes need to be checked first.
+var msvcPrefixedFlags = []string{
+	"/external:I",
+	"/imsvc",
+	"/FI",
+	"/D",
+	"/U",
+	"/I",
+}
+
+// msvcOnlyFlagRegex matches the cl.exe flags that have no clang equivalent and get dropped. Anything
+// else starting with a slash (eg. a Unix absolute path) is kept as is.
+var msvcOnlyFlagRegex = regexp.MustCompile(`^/(?:` +
+	`nologo|bigobj|permissive-?|utf-8|sdl-?|showIncludes|Brepro|FC|FS|J|JMC-?|MP\d*|` +
+	`G[dvzrTFLmswy]-?|GS-?|Gs\d*|Ob[0-3]|O[12dgistxy]-?|MDd?|MTd?|LDd?|` +
+	`Z[7iIl]|Zo-?|Zp\d*|Zm\d+|Zc:\S+|EH[acrs-]+|RTC\w*|` +
+	`w[de]\d+|w[1-4]\d+|wo\d+|Y[cu].*|Yl.*|Y-|F[adeimop].+|F[rR].*|` +
+	`analyze\S*|arch:\S+|fp:\S+|favor:\S+|errorReport:\S+|diagnostics:\S+|` +
+	`source-charset:\S+|execution-charset:\S+|validate-charset-?|experimental:\S+|external:\S+|` +
+	`volatile:\S+|guard:\S+|constexpr:\S+|cgthreads\d+|clang:\S+|d[12]\S+|Q[\w-]+(?::\w+)?` +
+	`)$`)
+
+// gnuTranslator rewrites cl.exe style arguments into clang driver style arguments.
+// Response files referenced by the arguments are translated as well and written into |rspDir|.
+type gnuTranslator struct {
+	rspDir string
+
+	mu          sync.Mutex
+	rspRewrites map[string]string
+}
+
+func newGNUTranslator(rspDir string) *gnuTranslator {
+	return &gnuTranslator{
+		rspDir:      rspDir,
+		rspRewrites: map[string]string{},
+	}
+}
+
+// TranslateEntry rewrites the arguments of |entry| in place.
+func (gt *gnuTranslator) TranslateEntry(entry *compdbEntry) error {
+	args, err := gt.TranslateArgs(entry.Arguments)
+	if err != nil {
+		return fmt.Errorf("translating arguments for %q: %w", entry.File, err)
+	}
+	entry.Arguments = args
+
+	return nil
+}
+
+// TranslateArgs rewrites cl.exe style |args| into their clang equivalent.
+// The first argument is considered to be the compiler.
+func (gt *gnuTranslator) TranslateArgs(args []string) ([]string, error) {
+	if len(args) == 0 {
+		return args, nil
+	}
+
+	result := make([]string, 0, len(args))
+	result = append(result, translateCompiler(args[0]))
+
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
+
+		// Response files get translated separately.
+		if strings.HasPrefix(arg, "@") {
+			rspPath, err := gt.translateRsp(strings.TrimPrefix(arg, "@"))
+			if err != nil {
+				return nil, fmt.Errorf("translating rsp %q: %w", arg, err)
+			}
+			result = append(result, "@"+rspPath)
+			continue
+		}
+
+		var next string
+		if i+1 < len(args) {
+			next = args[i+1]
+		}
+
+		translated, consumed := translateMSVCArg(arg, next)
+		result = append(result, translated...)
+		if consumed {
+			i++
+		}
+	}
+
+	return result, nil
+}
+
+// translateRsp translates the response file at |path| and returns the path to the translated one.
+// Translated files are cached, as UBT normally shares response files between entries.
+func (gt *gnuTranslator) translateRsp(path string) (string, error) {
+	gt.mu.Lock()
+	defer gt.mu.Unlock()
+
+	if rewritten, ok := gt.rspRewrites[path]; ok {
+		return rewritten, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("reading %q: %w", path, err)
+	}
+
+	translated := translateRspArgs(splitRspArgs(string(data)))
+
+	// We name the translated file after the original path, so that different response files with
+	// the same name don't collide.
+	hash := sha1.Sum([]byte(path))
+	name := fmt.Sprintf("%s_%s", hex.EncodeToString(hash[:4]), filepath.Base(path))
+	rspPath := filepath.Join(gt.rspDir, name)
+
+	if err := writeFileAtomically(rspPath, []byte(joinRspArgs(translated)), false); err != nil {
+		return "", fmt.Errorf("writing %q: %w", rspPath, err)
+	}
+
+	gt.rspRewrites[path] = rspPath
+	return rspPath, nil
+}
+
+// translateRspArgs translates the arguments found within a response file.
+// Unlike the command line, there is no compiler as the first argument.
+func translateRspArgs(args []string) []string {
+	var translated []string
+	for i := 0; i < len(args); i++ {
+		var next string
+		if i+1 < len(args) {
+			next = args[i+1]
+		}
+
+		result, consumed := translateMSVCArg(args[i], next)
+		translated = append(translated, result...)
+		if consumed {
+			i++
+		}
+	}
+
+	return translated
+}
+
+// translateCompiler maps the cl.exe compiler into the clang driver.
+func translateCompiler(compiler string) string {
+	base := strings.ToLower(filepath.Base(files.ToUnixPath(compiler)))
+	switch base {
+	case "cl.exe", "cl", "clang-cl.exe", "clang-cl":
+		return "clang++"
+	}
+
+	return compiler
+}
+
+// translateMSVCArg translates a single cl.exe argument. |next| is the following argument, which
+// some flags take as their value. Returns whether |next| was consumed.
+// Known flags that do not have a clang equivalent are dropped, and unknown ones are kept as is.
+func translateMSVCArg(arg, next string) ([]string, bool) {
+	if !strings.HasPrefix(arg, "/") {
+		// Already clang style or a positional argument.
+		return []string{arg}, false
+	}
+
+	if translated, ok := gnuDirectFlags[arg]; ok {
+		return translated, false
+	}
+
+	if std, ok := strings.CutPrefix(arg, "/std:"); ok {
+		if gnu, ok := gnuStdVersions[std]; ok {
+			return []string{"-std=" + gnu}, false
+		}
+		return nil, false
+	}
+
+	for _, prefix := range msvcPrefixedFlags {
+		if !strings.HasPrefix(arg, prefix) {
+			continue
+		}
+
+		value := strings.TrimPrefix(arg, prefix)
+		consumed := false
+		if value == "" {
+			value = next
+			consumed = true
+		}
+
+		switch prefix {
+		case "/FI":
+			return []string{"-include", value}, consumed
+		case "/D":
+			// Otherwise it's a path that happens to start like the flag (eg. /Developer/Foo.cpp).
+			if name, _, _ := strings.Cut(value, "="); !gCppIdentifierRegex.MatchString(name) {
+				return []string{arg}, false
+			}
+			return []string{"-D" + value}, consumed
+		case "/U":
+			if !gCppIdentifierRegex.MatchString(value) {
+				return []string{arg}, false
+			}
+			return []string{"-U" + value}, consumed
+		case "/I":
+			return []string{"-I" + value}, consumed
+		case "/external:I", "/imsvc":
+			return []string{"-isystem", value}, consumed
+		}
+	}
+
+	if msvcOnlyFlagRegex.MatchString(arg) {
+		return nil, false
+	}
+
+	// Not a cl.exe flag we know about, most likely a Unix absolute path.
+	return []string{arg}, false
+}
+
+// splitRspArgs tokenizes the content of a response file, respecting double quotes.
+func splitRspArgs(content string) []string {
+	var args []string
+	var sb strings.Builder
+	inQuotes := false
+	hasToken := false
+
+	for _, r := range content {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+			hasToken = true
+		case !inQuotes && (r == ' ' || r == '\t' || r == '\n' || r == '\r'):
+			if hasToken {
+				args = append(args, sb.String())
+				sb.Reset()
+				hasToken = false
+			}
+		default:
+			sb.WriteRune(r)
+			hasToken = true
+		}
+	}
+
+	if hasToken {
+		args = append(args, sb.String())
+	}
+
+	return args
+}
+
+// gRspEscaper escapes the characters that are special within a quoted GNU response file argument.
+var gRspEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// joinRspArgs writes |args| as the content of a response file, one argument per line.
+func joinRspArgs(args []string) string {
+	lines := make([]string, 0, len(args))
+	for _, arg := range args {
+		// GNU style response files treat backslashes as escapes, so we quote those too. Within the
+		// quotes only backslashes and quotes are escaped.
+		if strings.ContainsAny(arg, " \t\\\"") {
+			arg = `"` + gRspEscaper.Replace(arg) + `"`
+		}
+		lines = append(lines, arg)
+	}
+
+	return strings.Join(lines, "\n")
+}
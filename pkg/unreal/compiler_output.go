@@ -0,0 +1,97 @@
+package unreal
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// gMSVCDiagnosticRegex matches cl.exe output: "C:\Foo.cpp(12,5): error C2065: 'Bar': undeclared".
+	gMSVCDiagnosticRegex = regexp.MustCompile(
+		`^\s*(.+?)\((\d+)(?:,(\d+))?\)\s*:\s*(fatal error|error|warning|note)\s*([A-Z]+\d+)?\s*:\s*(.*)$`)
+
+	// gClangDiagnosticRegex matches clang output: "/foo/Foo.cpp:12:5: error: use of undeclared...".
+	gClangDiagnosticRegex = regexp.MustCompile(
+		`^\s*(.+?):(\d+):(\d+):\s*(fatal error|error|warning|note):\s*(.*?)(?:\s+\[([\w,=-]+)\])?$`)
+)
+
+// ParseCompilerDiagnostics extracts the compiler diagnostics (MSVC and clang formats) from the output
+// of a build. Lines that are not diagnostics are ignored. Repeated diagnostics (eg. the same header
+// warning reported for every translation unit) are only returned once, in order of appearance.
+func ParseCompilerDiagnostics(r io.Reader) ([]*Diagnostic, error) {
+	seen := map[string]struct{}{}
+
+	var diagnostics []*Diagnostic
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		diagnostic := parseCompilerDiagnostic(strings.TrimRight(scanner.Text(), "\r"))
+		if diagnostic == nil {
+			continue
+		}
+
+		key := diagnostic.String()
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+
+		diagnostics = append(diagnostics, diagnostic)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading compiler output: %w", err)
+	}
+
+	return diagnostics, nil
+}
+
+// parseCompilerDiagnostic returns the diagnostic within |line|, or nil if it does not have one.
+func parseCompilerDiagnostic(line string) *Diagnostic {
+	if match := gMSVCDiagnosticRegex.FindStringSubmatch(line); match != nil {
+		return &Diagnostic{
+			File:     strings.TrimSpace(match[1]),
+			Line:     atoiOrZero(match[2]),
+			Column:   atoiOrZero(match[3]),
+			Severity: compilerSeverity(match[4]),
+			Code:     match[5],
+			Message:  strings.TrimSpace(match[6]),
+		}
+	}
+
+	if match := gClangDiagnosticRegex.FindStringSubmatch(line); match != nil {
+		return &Diagnostic{
+			File:     strings.TrimSpace(match[1]),
+			Line:     atoiOrZero(match[2]),
+			Column:   atoiOrZero(match[3]),
+			Severity: compilerSeverity(match[4]),
+			Code:     match[6],
+			Message:  strings.TrimSpace(match[5]),
+		}
+	}
+
+	return nil
+}
+
+func compilerSeverity(severity string) DiagnosticSeverity {
+	switch severity {
+	case "warning":
+		return DiagnosticSeverity_Warning
+	case "note":
+		return DiagnosticSeverity_Note
+	default:
+		return DiagnosticSeverity_Error
+	}
+}
+
+func atoiOrZero(s string) int {
+	value, err := strconv.Atoi(s)
+	if err != nil {
+		return 0
+	}
+	return value
+}
@@ -0,0 +1,216 @@
+package unreal
+
+import (
+	"sort"
+	"strings"
+)
+
+// This file holds the lightweight C++ scanning utilities used by the indexers. None of this is a
+// real C++ parser: it's meant to be fast and good enough for the conventions Unreal code follows.
+
+// stripCppComments replaces all the comments in |data| with spaces. Newlines are kept, so offsets
+// and line numbers within the result match the original. String and char literals are respected.
+func stripCppComments(data []byte) []byte {
+	result := make([]byte, len(data))
+	copy(result, data)
+
+	const (
+		stateCode = iota
+		stateLineComment
+		stateBlockComment
+		stateString
+		stateChar
+	)
+
+	state := stateCode
+	for i := 0; i < len(result); i++ {
+		c := result[i]
+		var next byte
+		if i+1 < len(result) {
+			next = result[i+1]
+		}
+
+		switch state {
+		case stateCode:
+			switch {
+			case c == '/' && next == '/':
+				state = stateLineComment
+				result[i], result[i+1] = ' ', ' '
+				i++
+			case c == '/' && next == '*':
+				state = stateBlockComment
+				result[i], result[i+1] = ' ', ' '
+				i++
+			case c == '"':
+				state = stateString
+			case c == '\'':
+				state = stateChar
+			}
+		case stateLineComment:
+			if c == '\n' {
+				state = stateCode
+			} else {
+				result[i] = ' '
+			}
+		case stateBlockComment:
+			if c == '*' && next == '/' {
+				state = stateCode
+				result[i], result[i+1] = ' ', ' '
+				i++
+			} else if c != '\n' {
+				result[i] = ' '
+			}
+		case stateString, stateChar:
+			quote := byte('"')
+			if state == stateChar {
+				quote = '\''
+			}
+
+			switch c {
+			case '\\':
+				i++
+			case quote, '\n':
+				state = stateCode
+			}
+		}
+	}
+
+	return result
+}
+
+// lineIndex maps byte offsets within a file to their 1-based line and column.
+type lineIndex struct {
+	// lineStarts holds the offset of the first byte of each line.
+	lineStarts []int
+}
+
+func newLineIndex(data []byte) *lineIndex {
+	li := &lineIndex{
+		lineStarts: []int{0},
+	}
+
+	for i, c := range data {
+		if c == '\n' {
+			li.lineStarts = append(li.lineStarts, i+1)
+		}
+	}
+
+	return li
+}
+
+// Line returns the 1-based line the |offset| is in.
+func (li *lineIndex) Line(offset int) int {
+	return sort.Search(len(li.lineStarts), func(i int) bool {
+		return li.lineStarts[i] > offset
+	})
+}
+
+// Column returns the 1-based column (in bytes) of |offset| within its line.
+func (li *lineIndex) Column(offset int) int {
+	line := li.Line(offset)
+	return offset - li.lineStarts[line-1] + 1
+}
+
+// extractParens returns the content within the parenthesis that opens at |open|, along with the
+// offset just past the closing parenthesis. Returns false if they are not balanced.
+func extractParens(text string, open int) (string, int, bool) {
+	if open >= len(text) || text[open] != '(' {
+		return "", 0, false
+	}
+
+	depth := 0
+	inString := false
+	for i := open; i < len(text); i++ {
+		c := text[i]
+
+		if inString {
+			switch c {
+			case '\\':
+				i++
+			case '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch c {
+		case '"':
+			inString = true
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return text[open+1 : i], i + 1, true
+			}
+		}
+	}
+
+	return "", 0, false
+}
+
+// splitTopLevel splits |text| by |sep|, ignoring separators within parenthesis, angle brackets or
+// string literals. Each element is trimmed and empty elements are dropped.
+func splitTopLevel(text string, sep byte) []string {
+	var result []string
+
+	depth := 0
+	inString := false
+	start := 0
+	flush := func(end int) {
+		if part := strings.TrimSpace(text[start:end]); part != "" {
+			result = append(result, part)
+		}
+		start = end + 1
+	}
+
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+
+		if inString {
+			switch c {
+			case '\\':
+				i++
+			case '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch c {
+		case '"':
+			inString = true
+		case '(', '<', '[', '{':
+			depth++
+		case ')', '>', ']', '}':
+			depth--
+		case sep:
+			if depth == 0 {
+				flush(i)
+			}
+		}
+	}
+	flush(len(text))
+
+	return result
+}
+
+// isIdentifierByte returns whether |c| can be part of a C++ identifier.
+func isIdentifierByte(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// lastIdentifier returns the last C++ identifier within |text|.
+func lastIdentifier(text string) string {
+	end := len(text)
+	for end > 0 && !isIdentifierByte(text[end-1]) {
+		end--
+	}
+
+	start := end
+	for start > 0 && isIdentifierByte(text[start-1]) {
+		start--
+	}
+
+	return text[start:end]
+}
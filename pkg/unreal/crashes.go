@@ -0,0 +1,359 @@
+package unreal
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cristiandonosoc/golib/pkg/files"
+)
+
+const (
+	// kCrashContextFile is the file the crash reporter writes within each crash folder.
+	kCrashContextFile = "CrashContext.runtime-xml"
+
+	// kCrashSignatureFrames is how many frames of the callstack identify a crash.
+	kCrashSignatureFrames = 5
+
+	// kTicksAtUnixEpoch are the FDateTime ticks (100ns since 0001-01-01) at 1970-01-01.
+	kTicksAtUnixEpoch = 621355968000000000
+
+	// kLogStartTimeMaxLines is how far into a log |logStartTime| looks for a timestamp.
+	kLogStartTimeMaxLines = 1000
+
+	// kUnknownFunctionFrame is the function of the frames that could not be symbolicated.
+	kUnknownFunctionFrame = "!UnknownFunction"
+)
+
+const (
+	DiagnosticCode_CorruptCrash = "corrupt-crash"
+)
+
+var (
+	// gCallstackAddressRegex matches the addresses some callstack frames start with.
+	gCallstackAddressRegex = regexp.MustCompile(`^0x[0-9a-fA-F]+\s+`)
+
+	// gCallstackLocationRegex matches the source location at the end of a frame: " [C:\Foo.cpp:12]".
+	gCallstackLocationRegex = regexp.MustCompile(`\s*\[[^\]]*\]\s*$`)
+
+	// gCallstackSystemModules are modules whose frames are not specific to a crash.
+	gCallstackSystemModules = []string{"kernelbase", "kernel32", "ntdll", "libc", "libpthread"}
+)
+
+// Crash is a crash report found in a Saved/Crashes folder.
+type Crash struct {
+	Dir                string    `json:"dir"`
+	GUID               string    `json:"guid"`
+	Type               string    `json:"type"`
+	ErrorMessage       string    `json:"error_message"`
+	EngineVersion      string    `json:"engine_version"`
+	BuildConfiguration string    `json:"build_configuration"`
+	Platform           string    `json:"platform"`
+	EngineMode         string    `json:"engine_mode"`
+	Time               time.Time `json:"time"`
+	Callstack          []string  `json:"callstack"`
+	// Signature identifies crashes with the same callstack. See |crashSignature|.
+	Signature string `json:"signature"`
+	// LogFile is the log of the run that crashed, if it could be found.
+	LogFile string `json:"log_file,omitempty"`
+}
+
+// TopFrame returns the first meaningful frame of the callstack, or empty if there is none.
+func (c *Crash) TopFrame() string {
+	frames := signatureFrames(c.Callstack)
+	if len(frames) == 0 {
+		return ""
+	}
+	return frames[0]
+}
+
+// CrashGroup are the crashes that share a callstack signature.
+type CrashGroup struct {
+	Signature    string    `json:"signature"`
+	ErrorMessage string    `json:"error_message"`
+	TopFrame     string    `json:"top_frame"`
+	FirstSeen    time.Time `json:"first_seen"`
+	LastSeen     time.Time `json:"last_seen"`
+	Crashes      []*Crash  `json:"crashes"`
+}
+
+// crashContextXML is the part of CrashContext.runtime-xml we care about.
+type crashContextXML struct {
+	RuntimeProperties struct {
+		CrashGUID          string `xml:"CrashGUID"`
+		CrashType          string `xml:"CrashType"`
+		ErrorMessage       string `xml:"ErrorMessage"`
+		EngineVersion      string `xml:"EngineVersion"`
+		BuildConfiguration string `xml:"BuildConfiguration"`
+		PlatformName       string `xml:"PlatformName"`
+		EngineMode         string `xml:"EngineMode"`
+		CallStack          string `xml:"CallStack"`
+		TimeOfCrash        int64  `xml:"TimeOfCrash"`
+	} `xml:"RuntimeProperties"`
+}
+
+// CrashesDir is where the crash reporter leaves the crashes of the project.
+func (p *Project) CrashesDir() string {
+	return filepath.Join(p.ProjectDir(), "Saved", "Crashes")
+}
+
+// Crashes loads every crash within |dir|, sorted by time. If |dir| is empty, the crashes of the
+// project are loaded, and the ones without their own copy of the log are linked to the project log of
+// the run that crashed. Folders without a crash context (eg. still being written) are skipped, and the
+// ones that cannot be read are returned as warnings instead of crashes.
+func (p *Project) Crashes(dir string) ([]*Crash, []*Diagnostic, error) {
+	projectCrashes := dir == ""
+	if projectCrashes {
+		dir = p.CrashesDir()
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		// A project that never crashed does not have the directory.
+		if projectCrashes && os.IsNotExist(err) {
+			return nil, nil, nil
+		}
+		return nil, nil, fmt.Errorf("reading %q: %w", dir, err)
+	}
+
+	var logs []string
+	if projectCrashes {
+		if logs, err = p.LogFiles(); err != nil {
+			return nil, nil, fmt.Errorf("listing logs: %w", err)
+		}
+	}
+
+	var crashes []*Crash
+	var warnings []*Diagnostic
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		crashDir := filepath.Join(dir, entry.Name())
+		if _, found, err := files.StatFile(filepath.Join(crashDir, kCrashContextFile)); err != nil {
+			return nil, nil, fmt.Errorf("statting crash context in %q: %w", crashDir, err)
+		} else if !found {
+			continue
+		}
+
+		// One corrupt crash (eg. the process died while writing it) should not hide the rest.
+		crash, err := LoadCrash(crashDir)
+		if err != nil {
+			warnings = append(warnings, &Diagnostic{
+				File:     filepath.Join(crashDir, kCrashContextFile),
+				Severity: DiagnosticSeverity_Warning,
+				Code:     DiagnosticCode_CorruptCrash,
+				Message:  fmt.Sprintf("skipping crash: %v", err),
+			})
+			continue
+		}
+
+		if crash.LogFile == "" {
+			crash.LogFile = logOfCrash(crash, logs)
+		}
+
+		crashes = append(crashes, crash)
+	}
+
+	sort.SliceStable(crashes, func(i, j int) bool {
+		return crashes[i].Time.Before(crashes[j].Time)
+	})
+
+	return crashes, warnings, nil
+}
+
+// LoadCrash reads the crash context within the crash folder |dir|.
+func LoadCrash(dir string) (*Crash, error) {
+	path := filepath.Join(dir, kCrashContextFile)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %q: %w", path, err)
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	context := &crashContextXML{}
+	if err := xml.Unmarshal(data, context); err != nil {
+		return nil, fmt.Errorf("unmarshalling %q: %w", path, err)
+	}
+	props := context.RuntimeProperties
+
+	crash := &Crash{
+		Dir:                dir,
+		GUID:               props.CrashGUID,
+		Type:               props.CrashType,
+		ErrorMessage:       strings.TrimSpace(props.ErrorMessage),
+		EngineVersion:      props.EngineVersion,
+		BuildConfiguration: props.BuildConfiguration,
+		Platform:           props.PlatformName,
+		EngineMode:         props.EngineMode,
+	}
+
+	for _, frame := range strings.Split(props.CallStack, "\n") {
+		if frame = strings.TrimSpace(frame); frame != "" {
+			crash.Callstack = append(crash.Callstack, frame)
+		}
+	}
+	crash.Signature = crashSignature(crash.Callstack, crash.ErrorMessage)
+
+	if props.TimeOfCrash > kTicksAtUnixEpoch {
+		crash.Time = time.Unix(0, (props.TimeOfCrash-kTicksAtUnixEpoch)*100).UTC()
+	} else if info, err := os.Stat(path); err == nil {
+		crash.Time = info.ModTime().UTC()
+	}
+
+	// The crash reporter copies the log of the run into the crash folder.
+	logs, err := filepath.Glob(filepath.Join(dir, "*.log"))
+	if err != nil {
+		return nil, fmt.Errorf("globbing logs in %q: %w", dir, err)
+	}
+	if len(logs) > 0 {
+		sort.Strings(logs)
+		crash.LogFile = logs[0]
+	}
+
+	return crash, nil
+}
+
+// logOfCrash returns which of |logs| (sorted by modification time) belongs to the run that crashed:
+// the first one that was still written to at the time of the crash and that started before it.
+func logOfCrash(crash *Crash, logs []string) string {
+	// Allow some slack, as the last line is written around the time the crash context is.
+	since := crash.Time.Add(-time.Minute)
+	for _, log := range logs {
+		info, err := os.Stat(log)
+		if err != nil || info.ModTime().Before(since) {
+			continue
+		}
+
+		// Otherwise a later run would be blamed when the log of the crash was already deleted.
+		if start := logStartTime(log); !start.IsZero() && start.After(crash.Time) {
+			return ""
+		}
+		return log
+	}
+	return ""
+}
+
+// logStartTime returns the time of the first timestamped record of the log at |path|, or the zero
+// time if it cannot be found near the start of the file.
+func logStartTime(path string) time.Time {
+	file, err := os.Open(path)
+	if err != nil {
+		return time.Time{}
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for i := 0; i < kLogStartTimeMaxLines && scanner.Scan(); i++ {
+		if record := parseLogLine(strings.TrimRight(scanner.Text(), "\r")); record != nil && !record.Time.IsZero() {
+			return record.Time
+		}
+	}
+	return time.Time{}
+}
+
+// GroupCrashes groups |crashes| by signature. The groups with most crashes go first.
+func GroupCrashes(crashes []*Crash) []*CrashGroup {
+	groups := map[string]*CrashGroup{}
+	var sorted []*CrashGroup
+	for _, crash := range crashes {
+		group, ok := groups[crash.Signature]
+		if !ok {
+			group = &CrashGroup{
+				Signature:    crash.Signature,
+				ErrorMessage: crash.ErrorMessage,
+				TopFrame:     crash.TopFrame(),
+				FirstSeen:    crash.Time,
+				LastSeen:     crash.Time,
+			}
+			groups[crash.Signature] = group
+			sorted = append(sorted, group)
+		}
+
+		group.Crashes = append(group.Crashes, crash)
+		if crash.Time.Before(group.FirstSeen) {
+			group.FirstSeen = crash.Time
+		}
+		if crash.Time.After(group.LastSeen) {
+			group.LastSeen = crash.Time
+		}
+	}
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if len(sorted[i].Crashes) != len(sorted[j].Crashes) {
+			return len(sorted[i].Crashes) > len(sorted[j].Crashes)
+		}
+		return sorted[i].LastSeen.After(sorted[j].LastSeen)
+	})
+
+	return sorted
+}
+
+// crashSignature hashes the first meaningful frames of |callstack|. Addresses and source locations
+// are left out, so that the same crash in different builds gets the same signature.
+// Unsymbolicated frames only tell the module, so when there are any the first line of |errorMessage|
+// is hashed too, as otherwise unrelated crashes in the same module would share a signature.
+// Crashes without callstack all share the empty signature.
+func crashSignature(callstack []string, errorMessage string) string {
+	frames := signatureFrames(callstack)
+	if len(frames) == 0 {
+		return ""
+	}
+	if len(frames) > kCrashSignatureFrames {
+		frames = frames[:kCrashSignatureFrames]
+	}
+
+	for _, frame := range frames {
+		if strings.Contains(frame, kUnknownFunctionFrame) {
+			message, _, _ := strings.Cut(errorMessage, "\n")
+			frames = append(frames, strings.TrimSpace(message))
+			break
+		}
+	}
+
+	sum := sha1.Sum([]byte(strings.Join(frames, "\n")))
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+// signatureFrames normalizes the frames of |callstack| and drops the ones from system modules.
+func signatureFrames(callstack []string) []string {
+	var frames []string
+	for _, frame := range callstack {
+		frame = gCallstackAddressRegex.ReplaceAllString(frame, "")
+		frame = gCallstackLocationRegex.ReplaceAllString(frame, "")
+		frame = strings.TrimSpace(frame)
+		if frame == "" || isSystemFrame(frame) {
+			continue
+		}
+		frames = append(frames, frame)
+	}
+	return frames
+}
+
+// isSystemFrame returns whether |frame| ("Module!Function()") belongs to an OS module.
+func isSystemFrame(frame string) bool {
+	module, _, found := strings.Cut(frame, "!")
+	if !found {
+		return false
+	}
+
+	module = strings.ToLower(module)
+	for _, system := range gCallstackSystemModules {
+		if strings.HasPrefix(module, system) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+package unreal
+
+import (
+	"context"
+	"fmt"
+)
+
+// SetPluginEnabled enables or disables |name| in the .uproject. Plugins that are not referenced
+// yet get a new reference.
+func (p *Project) SetPluginEnabled(name string, enabled bool) error {
+	return p.editUProject(func(doc *descriptorDocument) error {
+		return doc.SetPluginEnabled(name, enabled)
+	})
+}
+
+// SetEngineAssociation sets the engine version (or the identifier of a source build) the .uproject
+// is associated with.
+func (p *Project) SetEngineAssociation(association string) error {
+	return p.editUProject(func(doc *descriptorDocument) error {
+		doc.Root.Set("EngineAssociation", association)
+		return nil
+	})
+}
+
+// RegisterModule adds |module| to the Modules of the .uproject, or of the .uplugin of |pluginName|
+// if set. The module has to exist in the index, as declaring a module without sources breaks the
+// project.
+func (p *Project) RegisterModule(ctx context.Context, module *UProjectModule, pluginName string) error {
+	if !p.IsIndexed() {
+		if err := p.IndexModules(ctx); err != nil {
+			return fmt.Errorf("indexing modules: %w", err)
+		}
+	}
+
+	indexed, ok := p.Modules[module.Name]
+	if !ok {
+		return fmt.Errorf("module %q not found in the project", module.Name)
+	}
+
+	var owner string
+	if indexed.Plugin != nil {
+		owner = indexed.Plugin.Name
+	}
+	if owner != pluginName {
+		if owner == "" {
+			return fmt.Errorf("module %q is a project module, not part of plugin %q", module.Name, pluginName)
+		}
+		return fmt.Errorf("module %q belongs to plugin %q", module.Name, owner)
+	}
+
+	return p.editDescriptor(pluginName, func(doc *descriptorDocument) error {
+		return doc.AddModule(module)
+	})
+}
+
+// UnregisterModule removes |name| from the Modules of the .uproject, or of the .uplugin of
+// |pluginName| if set. The module files are not touched.
+func (p *Project) UnregisterModule(name, pluginName string) error {
+	return p.editDescriptor(pluginName, func(doc *descriptorDocument) error {
+		return doc.RemoveModule(name)
+	})
+}
+
+// editDescriptor applies |edit| to the .uproject, or the .uplugin of |pluginName| if set.
+func (p *Project) editDescriptor(pluginName string, edit func(doc *descriptorDocument) error) error {
+	if pluginName == "" {
+		return p.editUProject(edit)
+	}
+
+	if p.Plugins == nil {
+		plugins, err := findPlugins(p.PluginsDir())
+		if err != nil {
+			return fmt.Errorf("finding plugins: %w", err)
+		}
+		p.Plugins = plugins
+	}
+
+	plugin, ok := p.Plugins[pluginName]
+	if !ok {
+		return fmt.Errorf("plugin %q not found", pluginName)
+	}
+
+	if err := editDescriptorFile(plugin.DescriptorPath, edit); err != nil {
+		return err
+	}
+
+	descriptor, err := loadUPluginFile(plugin.DescriptorPath)
+	if err != nil {
+		return fmt.Errorf("reloading plugin descriptor: %w", err)
+	}
+	plugin.Descriptor = descriptor
+
+	return nil
+}
+
+// editUProject applies |edit| to the .uproject and reloads it.
+func (p *Project) editUProject(edit func(doc *descriptorDocument) error) error {
+	if p.Config.UProjectPath == "" {
+		return fmt.Errorf("no uproject configured")
+	}
+
+	if err := editDescriptorFile(p.Config.UProjectPath, edit); err != nil {
+		return err
+	}
+
+	uproject, err := loadUProjectFile(p.Config.UProjectPath)
+	if err != nil {
+		return fmt.Errorf("reloading uproject file: %w", err)
+	}
+	p.LoadedUProject = uproject
+
+	return nil
+}
+
+func editDescriptorFile(path string, edit func(doc *descriptorDocument) error) error {
+	doc, err := loadDescriptorDocument(path)
+	if err != nil {
+		return fmt.Errorf("loading descriptor: %w", err)
+	}
+
+	if err := edit(doc); err != nil {
+		return err
+	}
+
+	if err := doc.Save(); err != nil {
+		return fmt.Errorf("saving descriptor: %w", err)
+	}
+
+	return nil
+}
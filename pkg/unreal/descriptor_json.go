@@ -0,0 +1,377 @@
+package unreal
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// jsonObject is a JSON object that remembers the order of its keys, so that descriptors can be
+// edited without reshuffling them. Values are *jsonObject, []any, json.Number, string, bool or nil.
+type jsonObject struct {
+	keys   []string
+	values map[string]any
+}
+
+func newJSONObject() *jsonObject {
+	return &jsonObject{
+		values: map[string]any{},
+	}
+}
+
+func (jo *jsonObject) Get(key string) (any, bool) {
+	value, ok := jo.values[key]
+	return value, ok
+}
+
+// GetString returns the value of |key| if it's a string.
+func (jo *jsonObject) GetString(key string) string {
+	value, _ := jo.values[key].(string)
+	return value
+}
+
+// Set replaces the value of |key|, keeping its position. New keys go at the end.
+func (jo *jsonObject) Set(key string, value any) {
+	if _, ok := jo.values[key]; !ok {
+		jo.keys = append(jo.keys, key)
+	}
+	jo.values[key] = value
+}
+
+func (jo *jsonObject) Delete(key string) {
+	if _, ok := jo.values[key]; !ok {
+		return
+	}
+
+	delete(jo.values, key)
+	for i, k := range jo.keys {
+		if k == key {
+			jo.keys = append(jo.keys[:i], jo.keys[i+1:]...)
+			break
+		}
+	}
+}
+
+// descriptorDocument is a .uproject or .uplugin file loaded for editing. Saving it only changes
+// what was edited: unknown fields, key order and the formatting conventions of the file are kept.
+type descriptorDocument struct {
+	Path string
+	Root *jsonObject
+
+	// Formatting of the original file.
+	newline         string
+	trailingNewline bool
+}
+
+func loadDescriptorDocument(path string) (*descriptorDocument, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %q: %w", path, err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	value, err := decodeOrderedJSON(dec)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %q: %w", path, err)
+	}
+
+	root, ok := value.(*jsonObject)
+	if !ok {
+		return nil, fmt.Errorf("parsing %q: descriptor is not a JSON object", path)
+	}
+
+	doc := &descriptorDocument{
+		Path:            path,
+		Root:            root,
+		newline:         "\n",
+		trailingNewline: bytes.HasSuffix(bytes.TrimRight(data, " \t"), []byte("\n")),
+	}
+	if bytes.Contains(data, []byte("\r\n")) {
+		doc.newline = "\r\n"
+	}
+
+	return doc, nil
+}
+
+// Marshal outputs the document the way Unreal writes descriptors: tab indented.
+func (doc *descriptorDocument) Marshal() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := encodeOrderedJSON(&buf, doc.Root, 0); err != nil {
+		return nil, err
+	}
+
+	data := buf.String()
+	if doc.trailingNewline {
+		data += "\n"
+	}
+	if doc.newline != "\n" {
+		data = strings.ReplaceAll(data, "\n", doc.newline)
+	}
+
+	return []byte(data), nil
+}
+
+// Save writes the document back to its path.
+func (doc *descriptorDocument) Save() error {
+	data, err := doc.Marshal()
+	if err != nil {
+		return fmt.Errorf("marshalling %q: %w", doc.Path, err)
+	}
+
+	if err := writeFileAtomically(doc.Path, data, false); err != nil {
+		return fmt.Errorf("writing %q: %w", doc.Path, err)
+	}
+
+	return nil
+}
+
+// Array returns the array at |key| of the root object. A missing key is an empty array.
+func (doc *descriptorDocument) Array(key string) ([]any, error) {
+	value, ok := doc.Root.Get(key)
+	if !ok || value == nil {
+		return nil, nil
+	}
+
+	array, ok := value.([]any)
+	if !ok {
+		return nil, fmt.Errorf("%q in %q is not an array", key, doc.Path)
+	}
+
+	return array, nil
+}
+
+// FindNamed returns the object within the |key| array whose "Name" is |name|, and its index.
+// Returns a -1 index if not found.
+func (doc *descriptorDocument) FindNamed(key, name string) (*jsonObject, int, error) {
+	array, err := doc.Array(key)
+	if err != nil {
+		return nil, -1, err
+	}
+
+	for i, value := range array {
+		if obj, ok := value.(*jsonObject); ok && obj.GetString("Name") == name {
+			return obj, i, nil
+		}
+	}
+
+	return nil, -1, nil
+}
+
+// AddModule appends |module| to the Modules array. Fails if a module with the same name exists.
+func (doc *descriptorDocument) AddModule(module *UProjectModule) error {
+	if existing, _, err := doc.FindNamed("Modules", module.Name); err != nil {
+		return err
+	} else if existing != nil {
+		return fmt.Errorf("module %q is already declared in %q", module.Name, doc.Path)
+	}
+
+	obj := newJSONObject()
+	obj.Set("Name", module.Name)
+	obj.Set("Type", module.Type)
+	obj.Set("LoadingPhase", module.LoadingPhase)
+	if len(module.AdditionalDependencies) > 0 {
+		obj.Set("AdditionalDependencies", stringsToJSON(module.AdditionalDependencies))
+	}
+	if len(module.TargetAllowList) > 0 {
+		obj.Set("TargetAllowList", stringsToJSON(module.TargetAllowList))
+	}
+	if len(module.TargetDenyList) > 0 {
+		obj.Set("TargetDenyList", stringsToJSON(module.TargetDenyList))
+	}
+
+	modules, _ := doc.Array("Modules")
+	doc.Root.Set("Modules", append(modules, obj))
+	return nil
+}
+
+// AddPlugin appends |plugin| to the Plugins array. Fails if the plugin is already referenced.
+func (doc *descriptorDocument) AddPlugin(plugin *UProjectPlugin) error {
+	if existing, _, err := doc.FindNamed("Plugins", plugin.Name); err != nil {
+		return err
+	} else if existing != nil {
+		return fmt.Errorf("plugin %q is already referenced in %q", plugin.Name, doc.Path)
+	}
+
+	obj := newJSONObject()
+	obj.Set("Name", plugin.Name)
+	obj.Set("Enabled", plugin.Enabled)
+	if len(plugin.TargetAllowList) > 0 {
+		obj.Set("TargetAllowList", stringsToJSON(plugin.TargetAllowList))
+	}
+	if plugin.WorkspaceURL != "" {
+		obj.Set("WorkspaceURL", plugin.WorkspaceURL)
+	}
+
+	plugins, _ := doc.Array("Plugins")
+	doc.Root.Set("Plugins", append(plugins, obj))
+	return nil
+}
+
+// RemoveModule deletes the module |name| from the Modules array.
+func (doc *descriptorDocument) RemoveModule(name string) error {
+	return doc.removeNamed("Modules", name)
+}
+
+// SetPluginEnabled enables or disables the plugin reference |name|, adding it if needed.
+// The rest of the fields of an existing reference (eg. TargetAllowList) are kept.
+func (doc *descriptorDocument) SetPluginEnabled(name string, enabled bool) error {
+	existing, _, err := doc.FindNamed("Plugins", name)
+	if err != nil {
+		return err
+	}
+
+	if existing == nil {
+		return doc.AddPlugin(&UProjectPlugin{Name: name, Enabled: enabled})
+	}
+
+	existing.Set("Enabled", enabled)
+	return nil
+}
+
+func (doc *descriptorDocument) removeNamed(key, name string) error {
+	_, index, err := doc.FindNamed(key, name)
+	if err != nil {
+		return err
+	}
+	if index < 0 {
+		return fmt.Errorf("%q not found in %s of %q", name, key, doc.Path)
+	}
+
+	array, _ := doc.Array(key)
+	doc.Root.Set(key, append(array[:index:index], array[index+1:]...))
+	return nil
+}
+
+func stringsToJSON(values []string) []any {
+	result := make([]any, 0, len(values))
+	for _, value := range values {
+		result = append(result, value)
+	}
+	return result
+}
+
+// decodeOrderedJSON reads the next value out of |dec|, keeping the order of the object keys.
+func decodeOrderedJSON(dec *json.Decoder) (any, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	delim, ok := token.(json.Delim)
+	if !ok {
+		return token, nil
+	}
+
+	switch delim {
+	case '{':
+		obj := newJSONObject()
+		for dec.More() {
+			keyToken, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			key, ok := keyToken.(string)
+			if !ok {
+				return nil, fmt.Errorf("expected object key, got %v", keyToken)
+			}
+
+			value, err := decodeOrderedJSON(dec)
+			if err != nil {
+				return nil, fmt.Errorf("key %q: %w", key, err)
+			}
+			obj.Set(key, value)
+		}
+		// Consume the closing brace.
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		return obj, nil
+
+	case '[':
+		array := []any{}
+		for dec.More() {
+			value, err := decodeOrderedJSON(dec)
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, value)
+		}
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		return array, nil
+	}
+
+	return nil, fmt.Errorf("unexpected delimiter %v", delim)
+}
+
+// encodeOrderedJSON writes |value| into |buf| with tab indentation, starting at |depth|.
+func encodeOrderedJSON(buf *bytes.Buffer, value any, depth int) error {
+	indent := strings.Repeat("\t", depth+1)
+	closingIndent := strings.Repeat("\t", depth)
+
+	switch v := value.(type) {
+	case *jsonObject:
+		if len(v.keys) == 0 {
+			buf.WriteString("{}")
+			return nil
+		}
+
+		buf.WriteString("{\n")
+		for i, key := range v.keys {
+			buf.WriteString(indent)
+			if err := encodeJSONScalar(buf, key); err != nil {
+				return err
+			}
+			buf.WriteString(": ")
+			if err := encodeOrderedJSON(buf, v.values[key], depth+1); err != nil {
+				return err
+			}
+			if i < len(v.keys)-1 {
+				buf.WriteString(",")
+			}
+			buf.WriteString("\n")
+		}
+		buf.WriteString(closingIndent + "}")
+		return nil
+
+	case []any:
+		if len(v) == 0 {
+			buf.WriteString("[]")
+			return nil
+		}
+
+		buf.WriteString("[\n")
+		for i, elem := range v {
+			buf.WriteString(indent)
+			if err := encodeOrderedJSON(buf, elem, depth+1); err != nil {
+				return err
+			}
+			if i < len(v)-1 {
+				buf.WriteString(",")
+			}
+			buf.WriteString("\n")
+		}
+		buf.WriteString(closingIndent + "]")
+		return nil
+
+	default:
+		return encodeJSONScalar(buf, v)
+	}
+}
+
+func encodeJSONScalar(buf *bytes.Buffer, value any) error {
+	var scalar bytes.Buffer
+	enc := json.NewEncoder(&scalar)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(value); err != nil {
+		return fmt.Errorf("encoding %v: %w", value, err)
+	}
+
+	buf.Write(bytes.TrimSuffix(scalar.Bytes(), []byte("\n")))
+	return nil
+}
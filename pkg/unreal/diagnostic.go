@@ -0,0 +1,76 @@
+package unreal
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DiagnosticSeverity is how serious a Diagnostic is.
+type DiagnosticSeverity string
+
+const (
+	DiagnosticSeverity_Error   DiagnosticSeverity = "error"
+	DiagnosticSeverity_Warning DiagnosticSeverity = "warning"
+	DiagnosticSeverity_Note    DiagnosticSeverity = "note"
+)
+
+// Diagnostic is a problem attributed to a location in a file, in the spirit of compiler output.
+type Diagnostic struct {
+	// File can be empty for diagnostics that are not about a file.
+	File string `json:"file"`
+	// Line and Column are 1-based. Zero means the diagnostic is about the whole file (or line).
+	Line     int                `json:"line,omitempty"`
+	Column   int                `json:"column,omitempty"`
+	Severity DiagnosticSeverity `json:"severity"`
+	// Code identifies the check that generated the diagnostic.
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message"`
+}
+
+// String outputs the diagnostic in the file:line:col format most editors know how to jump to.
+func (d *Diagnostic) String() string {
+	var sb strings.Builder
+	// Tool output (eg. UAT) can have diagnostics that are not about any file.
+	if d.File != "" {
+		sb.WriteString(d.File)
+		if d.Line > 0 {
+			sb.WriteString(fmt.Sprintf(":%d", d.Line))
+			if d.Column > 0 {
+				sb.WriteString(fmt.Sprintf(":%d", d.Column))
+			}
+		}
+		sb.WriteString(": ")
+	}
+	sb.WriteString(fmt.Sprintf("%s: %s", d.Severity, d.Message))
+	if d.Code != "" {
+		sb.WriteString(fmt.Sprintf(" [%s]", d.Code))
+	}
+
+	return sb.String()
+}
+
+// SortDiagnostics sorts the diagnostics by location.
+func SortDiagnostics(diagnostics []*Diagnostic) {
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		a, b := diagnostics[i], diagnostics[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+}
+
+// CountDiagnostics returns how many of |diagnostics| have |severity|.
+func CountDiagnostics(diagnostics []*Diagnostic, severity DiagnosticSeverity) int {
+	count := 0
+	for _, d := range diagnostics {
+		if d.Severity == severity {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,358 @@
+package unreal
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+const (
+	DiagnosticCode_MissingModule               = "missing-module"
+	DiagnosticCode_UndeclaredModule            = "undeclared-module"
+	DiagnosticCode_ModuleInWrongDescriptor     = "module-in-wrong-descriptor"
+	DiagnosticCode_InvalidModuleType           = "invalid-module-type"
+	DiagnosticCode_InvalidLoadingPhase         = "invalid-loading-phase"
+	DiagnosticCode_ModuleTypeMismatch          = "module-type-mismatch"
+	DiagnosticCode_LoadingPhaseMismatch        = "loading-phase-mismatch"
+	DiagnosticCode_UnknownAdditionalDependency = "unknown-additional-dependency"
+	DiagnosticCode_MissingPlugin               = "missing-plugin"
+)
+
+// Doctor cross checks the .uproject and .uplugin descriptors against the indexed source:
+//
+//   - Modules declared but not found on disk, or found on disk but not declared.
+//   - Modules declared in a descriptor other than the one that owns them.
+//   - Invalid module types and loading phases, and types that don't match the module.
+//   - Types and loading phases that don't match where the module lives: runtime modules in editor
+//     only plugins, editor modules that game targets ask for and engine only loading phases.
+//   - AdditionalDependencies that name modules that don't exist.
+//   - Enabled plugins that exist neither in the project nor in the engine.
+//
+// These mismatches normally only show up as "module could not be loaded" errors at startup.
+func (p *Project) Doctor(ctx context.Context) ([]*Diagnostic, error) {
+	if !p.IsIndexed() {
+		if err := p.IndexModules(ctx); err != nil {
+			return nil, fmt.Errorf("indexing modules: %w", err)
+		}
+	}
+
+	var diagnostics []*Diagnostic
+
+	if p.LoadedUProject != nil {
+		uprojectDiagnostics, err := p.checkDescriptorModules(p.Config.UProjectPath, p.LoadedUProject.Modules, nil)
+		if err != nil {
+			return nil, err
+		}
+		diagnostics = append(diagnostics, uprojectDiagnostics...)
+
+		pluginDiagnostics, err := p.checkPluginReferences()
+		if err != nil {
+			return nil, err
+		}
+		diagnostics = append(diagnostics, pluginDiagnostics...)
+	}
+
+	for _, plugin := range p.Plugins {
+		if plugin.Descriptor == nil {
+			continue
+		}
+
+		pluginDiagnostics, err := p.checkDescriptorModules(plugin.DescriptorPath, plugin.Descriptor.Modules, plugin)
+		if err != nil {
+			return nil, err
+		}
+		diagnostics = append(diagnostics, pluginDiagnostics...)
+	}
+
+	SortDiagnostics(diagnostics)
+	return diagnostics, nil
+}
+
+// checkDescriptorModules checks the |declared| modules of the descriptor at |path|, which belongs to
+// |owner| (nil for the .uproject).
+func (p *Project) checkDescriptorModules(path string, declared []*UProjectModule, owner *Plugin) ([]*Diagnostic, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %q: %w", path, err)
+	}
+
+	newDiagnostic := func(name string, severity DiagnosticSeverity, code, format string, args ...any) *Diagnostic {
+		return &Diagnostic{
+			File:     path,
+			Line:     findQuotedLine(data, name),
+			Severity: severity,
+			Code:     code,
+			Message:  fmt.Sprintf(format, args...),
+		}
+	}
+
+	var diagnostics []*Diagnostic
+	declaredNames := map[string]struct{}{}
+	for _, descriptor := range declared {
+		declaredNames[descriptor.Name] = struct{}{}
+
+		if _, err := NewModuleHostType(descriptor.Type); err != nil {
+			diagnostics = append(diagnostics, newDiagnostic(descriptor.Name, DiagnosticSeverity_Error,
+				DiagnosticCode_InvalidModuleType, "module %s has invalid type %q", descriptor.Name, descriptor.Type))
+		}
+		if descriptor.LoadingPhase != "" {
+			if _, err := NewModuleLoadingPhase(descriptor.LoadingPhase); err != nil {
+				diagnostics = append(diagnostics, newDiagnostic(descriptor.Name, DiagnosticSeverity_Error,
+					DiagnosticCode_InvalidLoadingPhase, "module %s has invalid loading phase %q",
+					descriptor.Name, descriptor.LoadingPhase))
+			}
+		}
+
+		module, ok := p.Modules[descriptor.Name]
+
+		for _, dep := range descriptor.AdditionalDependencies {
+			if p.isKnownModule(dep) {
+				continue
+			}
+			if ok && module.Rules != nil && slices.Contains(module.Rules.AllDependencies(), dep) {
+				continue
+			}
+
+			diagnostics = append(diagnostics, newDiagnostic(dep, DiagnosticSeverity_Warning,
+				DiagnosticCode_UnknownAdditionalDependency,
+				"additional dependency %s of module %s is not a known module nor a dependency in its build file",
+				dep, descriptor.Name))
+		}
+
+		if !ok {
+			diagnostics = append(diagnostics, newDiagnostic(descriptor.Name, DiagnosticSeverity_Error,
+				DiagnosticCode_MissingModule, "module %s is declared but its source was not found", descriptor.Name))
+			continue
+		}
+
+		if module.Plugin != owner {
+			where := "the project Source directory"
+			if module.Plugin != nil {
+				where = fmt.Sprintf("plugin %s", module.Plugin.Name)
+			}
+			diagnostics = append(diagnostics, newDiagnostic(descriptor.Name, DiagnosticSeverity_Error,
+				DiagnosticCode_ModuleInWrongDescriptor, "module %s is declared here, but it lives in %s",
+				descriptor.Name, where))
+		}
+
+		diagnostics = append(diagnostics, p.checkModuleType(module, descriptor, newDiagnostic)...)
+		diagnostics = append(diagnostics, p.checkModuleLocation(module, descriptor, newDiagnostic)...)
+	}
+
+	// Dependents let us tell apart helper modules (only used through other modules) from modules that
+	// are just never loaded.
+	dependents := map[string][]string{}
+	for _, module := range p.Modules {
+		if module.Rules == nil {
+			continue
+		}
+		for _, dep := range module.Rules.AllDependencies() {
+			dependents[dep] = append(dependents[dep], module.Name)
+		}
+	}
+
+	for _, module := range p.sortedModules() {
+		if module.Plugin != owner {
+			continue
+		}
+		if _, ok := declaredNames[module.Name]; ok {
+			continue
+		}
+
+		message := fmt.Sprintf("module %s (%s) is not declared, so it will never be loaded on its own",
+			module.Name, module.BaseDir)
+		if users := sortedUnique(dependents[module.Name]); len(users) > 0 {
+			message = fmt.Sprintf("module %s (%s) is not declared. It is only built as a dependency of %s",
+				module.Name, module.BaseDir, strings.Join(users, ", "))
+		}
+		diagnostics = append(diagnostics, &Diagnostic{
+			File:     path,
+			Severity: DiagnosticSeverity_Warning,
+			Code:     DiagnosticCode_UndeclaredModule,
+			Message:  message,
+		})
+	}
+
+	return diagnostics, nil
+}
+
+// checkModuleType reports declared types that don't match the module: editor modules declared as
+// runtime ones (which break cooked builds) and runtime modules depending on editor only modules.
+func (p *Project) checkModuleType(module *Module, descriptor *UProjectModule,
+	newDiagnostic func(name string, severity DiagnosticSeverity, code, format string, args ...any) *Diagnostic) []*Diagnostic {
+	var diagnostics []*Diagnostic
+
+	builtInGame := TargetType_Game.AllowsModuleType(descriptor.Type)
+	if builtInGame && strings.HasSuffix(module.Name, "Editor") {
+		diagnostics = append(diagnostics, newDiagnostic(module.Name, DiagnosticSeverity_Warning,
+			DiagnosticCode_ModuleTypeMismatch,
+			"module %s looks like an editor module, but its type %s also builds it into game targets",
+			module.Name, descriptor.Type))
+	}
+
+	if !builtInGame || module.Rules == nil {
+		return diagnostics
+	}
+
+	for _, dep := range module.Rules.AllDependencies() {
+		depModule, ok := p.Modules[dep]
+		if !ok {
+			continue
+		}
+
+		depDescriptor := p.moduleDescriptor(depModule)
+		if depDescriptor == nil || TargetType_Game.AllowsModuleType(depDescriptor.Type) {
+			continue
+		}
+
+		diagnostics = append(diagnostics, newDiagnostic(module.Name, DiagnosticSeverity_Warning,
+			DiagnosticCode_ModuleTypeMismatch,
+			"module %s (%s) depends on %s, which is %s only. Make sure the dependency is conditional on the target",
+			module.Name, descriptor.Type, dep, depDescriptor.Type))
+	}
+
+	return diagnostics
+}
+
+// gEngineLoadingPhases are the loading phases that run before the engine can host project code.
+var gEngineLoadingPhases = []string{"EarliestPossible", "PostConfigInit"}
+
+// gCookedTargetTypes are the target types that only build the modules that are allowed in cooked
+// builds.
+var gCookedTargetTypes = []TargetType{TargetType_Game, TargetType_Client, TargetType_Server}
+
+// checkModuleLocation reports declared types and loading phases that don't match where the module
+// lives: runtime modules in plugins that are only enabled for the editor, editor modules in the
+// project Source that game targets ask for and project modules using engine only loading phases.
+func (p *Project) checkModuleLocation(module *Module, descriptor *UProjectModule,
+	newDiagnostic func(name string, severity DiagnosticSeverity, code, format string, args ...any) *Diagnostic) []*Diagnostic {
+	var diagnostics []*Diagnostic
+
+	if module.Plugin != nil && p.pluginIsEditorOnly(module.Plugin) {
+		for _, targetType := range gCookedTargetTypes {
+			if !targetType.AllowsModuleType(descriptor.Type) {
+				continue
+			}
+
+			diagnostics = append(diagnostics, newDiagnostic(module.Name, DiagnosticSeverity_Warning,
+				DiagnosticCode_ModuleTypeMismatch,
+				"module %s is %s, but plugin %s is only enabled for editor targets. Declare it as an editor module",
+				module.Name, descriptor.Type, module.Plugin.Name))
+			break
+		}
+	}
+
+	if module.Plugin == nil {
+		for _, target := range p.sortedTargets() {
+			if !slices.Contains(gCookedTargetTypes, target.Type) || target.Type.AllowsModuleType(descriptor.Type) {
+				continue
+			}
+			if !slices.Contains(target.ExtraModuleNames, module.Name) {
+				continue
+			}
+
+			diagnostics = append(diagnostics, newDiagnostic(module.Name, DiagnosticSeverity_Error,
+				DiagnosticCode_ModuleTypeMismatch,
+				"module %s is %s, but target %s asks for it, so %s builds will not find it",
+				module.Name, descriptor.Type, target.Name, target.Type))
+		}
+	}
+
+	if slices.Contains(gEngineLoadingPhases, descriptor.LoadingPhase) {
+		diagnostics = append(diagnostics, newDiagnostic(module.Name, DiagnosticSeverity_Warning,
+			DiagnosticCode_LoadingPhaseMismatch,
+			"module %s loads at %s, which is meant for engine modules: the engine is not ready for project code yet. "+
+				"Use Default, PreDefault or PostEngineInit instead", module.Name, descriptor.LoadingPhase))
+	}
+
+	return diagnostics
+}
+
+// pluginIsEditorOnly returns whether the .uproject only enables |plugin| for editor targets.
+func (p *Project) pluginIsEditorOnly(plugin *Plugin) bool {
+	if !p.pluginAllowedInTarget(plugin, &Target{Type: TargetType_Editor}) {
+		return false
+	}
+
+	for _, targetType := range gCookedTargetTypes {
+		if p.pluginAllowedInTarget(plugin, &Target{Type: targetType}) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkPluginReferences reports the enabled plugins of the .uproject that cannot be found.
+func (p *Project) checkPluginReferences() ([]*Diagnostic, error) {
+	if len(p.LoadedUProject.Plugins) == 0 {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(p.Config.UProjectPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading %q: %w", p.Config.UProjectPath, err)
+	}
+
+	enginePlugins, err := p.enginePlugins()
+	if err != nil {
+		return nil, err
+	}
+
+	var diagnostics []*Diagnostic
+	for _, ref := range p.LoadedUProject.Plugins {
+		if !ref.Enabled {
+			continue
+		}
+		if _, ok := p.Plugins[ref.Name]; ok {
+			continue
+		}
+
+		severity := DiagnosticSeverity_Error
+		message := fmt.Sprintf("plugin %s is enabled but does not exist in the project nor the engine", ref.Name)
+		if enginePlugins == nil {
+			severity = DiagnosticSeverity_Warning
+			message = fmt.Sprintf("plugin %s is enabled but does not exist in the project (engine plugins were not checked)", ref.Name)
+		} else if _, ok := enginePlugins[ref.Name]; ok {
+			continue
+		}
+
+		diagnostics = append(diagnostics, &Diagnostic{
+			File:     p.Config.UProjectPath,
+			Line:     findQuotedLine(data, ref.Name),
+			Severity: severity,
+			Code:     DiagnosticCode_MissingPlugin,
+			Message:  message,
+		})
+	}
+
+	return diagnostics, nil
+}
+
+// isKnownModule returns whether |name| is a module of the project (or of the engine, if indexed).
+func (p *Project) isKnownModule(name string) bool {
+	_, ok := p.LookupModule(name)
+	return ok
+}
+
+// enginePlugins returns the plugins of the configured engine. Returns nil if there is no engine
+// configured.
+func (p *Project) enginePlugins() (map[string]*Plugin, error) {
+	if p.Engine != nil {
+		return p.Engine.Plugins, nil
+	}
+
+	engineDir := p.EngineDir()
+	if engineDir == "" {
+		return nil, nil
+	}
+
+	plugins, err := findEnginePlugins(filepath.Join(engineDir, "Plugins"))
+	if err != nil {
+		return nil, fmt.Errorf("finding engine plugins: %w", err)
+	}
+
+	return plugins, nil
+}
@@ -0,0 +1,368 @@
+package unreal
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cristiandonosoc/golib/pkg/files"
+)
+
+const (
+	// Bump when the format of the cached engine index changes, or when the way it is calculated does.
+	// 2: files are attributed to modules on path boundaries (Core no longer holds CoreUObject files).
+	kEngineIndexCacheVersion = 2
+)
+
+// EngineIndex holds the modules and plugins of the engine the project uses.
+type EngineIndex struct {
+	// Key identifies the engine build: version, changelist and location.
+	Key     string
+	Dir     string
+	Modules map[string]*Module
+	Plugins map[string]*Plugin
+
+	// publicHeaders maps the include path of each public engine header (relative to the Public or
+	// Classes directory of its module, lowercase) to the header.
+	publicHeaders map[string]string
+}
+
+// engineIndexCache is the on-disk format of an EngineIndex.
+type engineIndexCache struct {
+	Version int                  `json:"version"`
+	Key     string               `json:"key"`
+	Dir     string               `json:"dir"`
+	Modules []*engineModuleCache `json:"modules"`
+	Plugins []*engineIndexPlugin `json:"plugins"`
+}
+
+type engineModuleCache struct {
+	Name      string       `json:"name"`
+	BaseDir   string       `json:"base_dir"`
+	BuildFile string       `json:"build_file"`
+	Files     []string     `json:"files"`
+	Plugin    string       `json:"plugin,omitempty"`
+	Rules     *ModuleRules `json:"rules"`
+}
+
+type engineIndexPlugin struct {
+	Name           string   `json:"name"`
+	BaseDir        string   `json:"base_dir"`
+	DescriptorPath string   `json:"descriptor_path"`
+	Descriptor     *UPlugin `json:"descriptor"`
+}
+
+// EngineDir returns the Engine directory of the configured editor, or empty if there is none.
+func (p *Project) EngineDir() string {
+	if p.Config.EditorConfig == nil || p.Config.EditorConfig.EditorDir == "" {
+		return ""
+	}
+	return filepath.Join(p.Config.EditorConfig.EditorDir, "Engine")
+}
+
+// IndexEngine indexes the modules and plugins of the engine. Scanning the engine is slow, so the
+// index is cached in the user cache directory per engine version and changelist. |reload| ignores
+// the cache.
+func (p *Project) IndexEngine(ctx context.Context, reload bool) error {
+	engineDir := p.EngineDir()
+	if engineDir == "" {
+		return fmt.Errorf("no editor configured")
+	}
+
+	key := p.engineIndexKey()
+	cachePath, err := engineIndexCachePath(key)
+	if err != nil {
+		return err
+	}
+
+	if !reload {
+		index, err := loadEngineIndexCache(cachePath, key)
+		if err != nil {
+			return fmt.Errorf("loading cached engine index: %w", err)
+		}
+		if index != nil {
+			p.Engine = index
+			return nil
+		}
+	}
+
+	index, err := collectEngineIndex(ctx, engineDir)
+	if err != nil {
+		return err
+	}
+	index.Key = key
+
+	if err := index.save(cachePath); err != nil {
+		return fmt.Errorf("caching engine index: %w", err)
+	}
+
+	p.Engine = index
+	return nil
+}
+
+// engineIndexKey identifies the engine build. The location is part of it, as two installations can
+// have the same version while having different content (eg. a source build with local changes).
+func (p *Project) engineIndexKey() string {
+	editor := p.Config.EditorConfig
+
+	version := "unknown"
+	if editor.Version != nil {
+		version = editor.Version.String()
+	}
+
+	changelist := 0
+	if bv := editor.BuildVersionFile; bv != nil {
+		changelist = bv.Changelist
+	}
+
+	dirHash := sha256.Sum256([]byte(filepath.Clean(editor.EditorDir)))
+	return fmt.Sprintf("%s-%d-%s", version, changelist, hex.EncodeToString(dirHash[:])[:12])
+}
+
+func engineIndexCachePath(key string) (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("finding user cache dir: %w", err)
+	}
+
+	return filepath.Join(cacheDir, "gunreal", "engine_index", key+".json"), nil
+}
+
+// collectEngineIndex scans Engine/Source and Engine/Plugins. Unlike the project, the engine has
+// modules with the same name (eg. platform specific variants), so duplicates are not an error.
+func collectEngineIndex(ctx context.Context, engineDir string) (*EngineIndex, error) {
+	keepFirst := func(existing, duplicate *Module) error { return nil }
+
+	modules, err := collectModulesWithDuplicates(ctx, filepath.Join(engineDir, "Source"), keepFirst)
+	if err != nil {
+		return nil, fmt.Errorf("collecting engine modules: %w", err)
+	}
+
+	plugins, err := findEnginePlugins(filepath.Join(engineDir, "Plugins"))
+	if err != nil {
+		return nil, fmt.Errorf("finding engine plugins: %w", err)
+	}
+
+	// Go over the plugins in order, so that the kept duplicates are deterministic.
+	names := make([]string, 0, len(plugins))
+	for name := range plugins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		plugin := plugins[name]
+		if exists, err := files.DirExists(plugin.SourceDir()); err != nil {
+			return nil, fmt.Errorf("querying plugin source dir %q: %w", plugin.SourceDir(), err)
+		} else if !exists {
+			continue
+		}
+
+		pluginModules, err := collectModulesWithDuplicates(ctx, plugin.SourceDir(), keepFirst)
+		if err != nil {
+			return nil, fmt.Errorf("collecting modules for engine plugin %q: %w", plugin.Name, err)
+		}
+
+		for moduleName, module := range pluginModules {
+			if _, ok := modules[moduleName]; ok {
+				continue
+			}
+
+			module.Plugin = plugin
+			modules[moduleName] = module
+		}
+	}
+
+	index := &EngineIndex{
+		Dir:     engineDir,
+		Modules: modules,
+		Plugins: plugins,
+	}
+	index.buildPublicHeaders()
+
+	return index, nil
+}
+
+// findEnginePlugins is |findPlugins| for the engine, where a few plugins exist more than once
+// (eg. platform extensions). The first one (by path) is kept.
+func findEnginePlugins(pluginsDir string) (map[string]*Plugin, error) {
+	descriptors, err := findPluginDescriptors(pluginsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	plugins := map[string]*Plugin{}
+	for _, path := range descriptors {
+		name := pluginNameFromDescriptor(path)
+		if _, ok := plugins[name]; ok {
+			continue
+		}
+
+		// Engine descriptors are not ours to validate: a broken one should not prevent indexing.
+		descriptor, _ := loadUPluginFile(path)
+
+		plugins[name] = &Plugin{
+			Name:           name,
+			BaseDir:        filepath.Dir(path),
+			DescriptorPath: path,
+			Descriptor:     descriptor,
+		}
+	}
+
+	return plugins, nil
+}
+
+func (ei *EngineIndex) buildPublicHeaders() {
+	ei.publicHeaders = map[string]string{}
+	for _, module := range ei.Modules {
+		for _, dir := range []string{module.PublicDir(), module.ClassesDir()} {
+			prefix := dir + string(filepath.Separator)
+			for _, file := range module.Files {
+				if !strings.HasPrefix(file, prefix) {
+					continue
+				}
+
+				key := includeKey(strings.TrimPrefix(file, prefix))
+				if existing, ok := ei.publicHeaders[key]; !ok || file < existing {
+					ei.publicHeaders[key] = file
+				}
+			}
+		}
+	}
+}
+
+// FindPublicHeader returns the engine header an #include of |include| would find, when searching
+// all the engine modules.
+func (ei *EngineIndex) FindPublicHeader(include string) (string, bool) {
+	header, ok := ei.publicHeaders[includeKey(include)]
+	return header, ok
+}
+
+func (ei *EngineIndex) save(path string) error {
+	cache := &engineIndexCache{
+		Version: kEngineIndexCacheVersion,
+		Key:     ei.Key,
+		Dir:     ei.Dir,
+	}
+
+	for _, name := range sortedKeys(ei.Modules) {
+		module := ei.Modules[name]
+		cached := &engineModuleCache{
+			Name:      module.Name,
+			BaseDir:   module.BaseDir,
+			BuildFile: module.BuildFile,
+			Files:     module.Files,
+			Rules:     module.Rules,
+		}
+		if module.Plugin != nil {
+			cached.Plugin = module.Plugin.Name
+		}
+		cache.Modules = append(cache.Modules, cached)
+	}
+
+	for _, name := range sortedKeys(ei.Plugins) {
+		plugin := ei.Plugins[name]
+		cache.Plugins = append(cache.Plugins, &engineIndexPlugin{
+			Name:           plugin.Name,
+			BaseDir:        plugin.BaseDir,
+			DescriptorPath: plugin.DescriptorPath,
+			Descriptor:     plugin.Descriptor,
+		})
+	}
+
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return fmt.Errorf("marshalling engine index: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating cache dir: %w", err)
+	}
+
+	return writeFileAtomically(path, data, false)
+}
+
+// loadEngineIndexCache loads the cached index at |path|. Returns nil if there is no usable cache.
+func loadEngineIndexCache(path, key string) (*EngineIndex, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading %q: %w", path, err)
+	}
+
+	cache := &engineIndexCache{}
+	if err := json.Unmarshal(data, cache); err != nil {
+		return nil, fmt.Errorf("unmarshalling %q: %w", path, err)
+	}
+
+	if cache.Version != kEngineIndexCacheVersion || cache.Key != key {
+		return nil, nil
+	}
+
+	index := &EngineIndex{
+		Key:     cache.Key,
+		Dir:     cache.Dir,
+		Modules: make(map[string]*Module, len(cache.Modules)),
+		Plugins: make(map[string]*Plugin, len(cache.Plugins)),
+	}
+
+	for _, cached := range cache.Plugins {
+		index.Plugins[cached.Name] = &Plugin{
+			Name:           cached.Name,
+			BaseDir:        cached.BaseDir,
+			DescriptorPath: cached.DescriptorPath,
+			Descriptor:     cached.Descriptor,
+		}
+	}
+
+	for _, cached := range cache.Modules {
+		index.Modules[cached.Name] = &Module{
+			Name:      cached.Name,
+			BaseDir:   cached.BaseDir,
+			BuildFile: cached.BuildFile,
+			Files:     cached.Files,
+			Plugin:    index.Plugins[cached.Plugin],
+			Rules:     cached.Rules,
+			uhtFiles:  map[Platform][]string{},
+		}
+	}
+	index.buildPublicHeaders()
+
+	return index, nil
+}
+
+// LookupModule searches |name| within the project modules and, if indexed, the engine ones.
+func (p *Project) LookupModule(name string) (*Module, bool) {
+	if module, ok := p.Modules[name]; ok {
+		return module, true
+	}
+
+	if p.Engine != nil {
+		module, ok := p.Engine.Modules[name]
+		return module, ok
+	}
+
+	return nil, false
+}
+
+// IsEngineModule returns whether |module| comes from the engine index.
+func (p *Project) IsEngineModule(module *Module) bool {
+	return p.Engine != nil && p.Engine.Modules[module.Name] == module
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
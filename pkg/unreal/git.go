@@ -0,0 +1,63 @@
+package unreal
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// runGit runs git within |dir| and returns its trimmed stdout.
+func runGit(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("running git %v: %w (%s)", args, err, strings.TrimSpace(stderr.String()))
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// ChangedFilesSince returns the (absolute) paths of the files that changed between |ref| and the
+// working tree of the git repository holding the project. This includes uncommitted changes.
+func (p *Project) ChangedFilesSince(ref string) ([]string, error) {
+	root, err := runGit(p.ProjectDir(), "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, fmt.Errorf("finding git root: %w", err)
+	}
+
+	// Using the merge base means we only get the changes of this branch, not the ones of |ref|.
+	base, err := runGit(p.ProjectDir(), "merge-base", ref, "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("finding merge base with %q: %w", ref, err)
+	}
+
+	output, err := runGit(p.ProjectDir(), "diff", "--name-only", base)
+	if err != nil {
+		return nil, fmt.Errorf("diffing against %q: %w", ref, err)
+	}
+
+	// Untracked files are changes too.
+	untracked, err := runGit(p.ProjectDir(), "ls-files", "--others", "--exclude-standard", "--full-name")
+	if err != nil {
+		return nil, fmt.Errorf("listing untracked files: %w", err)
+	}
+
+	var changed []string
+	for _, line := range strings.Split(output+"\n"+untracked, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		changed = append(changed, filepath.Join(root, filepath.FromSlash(line)))
+	}
+
+	return sortedUnique(changed), nil
+}
@@ -0,0 +1,197 @@
+package unreal
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+)
+
+const (
+	// kGrepBinarySniffSize is how many bytes we check for NUL characters to consider a file binary.
+	kGrepBinarySniffSize = 8000
+	kGrepMaxLineSize     = 16 * 1024 * 1024
+)
+
+// GrepOptions scopes which files a grep goes over.
+type GrepOptions struct {
+	// Modules restricts the search to these modules. Empty means all of them.
+	Modules []string
+	// Plugins restricts the search to the modules of these plugins. Empty means all of them.
+	Plugins []string
+
+	// IncludeUHT also searches the UHT generated files of the modules for |Platform|.
+	IncludeUHT bool
+	Platform   Platform
+}
+
+// GrepMatch is a single match of a grep.
+type GrepMatch struct {
+	Module *Module
+	File   string
+	Line   int
+	Column int
+	Text   string
+}
+
+func (gm *GrepMatch) String() string {
+	return fmt.Sprintf("%s:%d:%d:%s", gm.File, gm.Line, gm.Column, gm.Text)
+}
+
+func (gm *GrepMatch) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&struct {
+		Module string `json:"module"`
+		File   string `json:"file"`
+		Line   int    `json:"line"`
+		Column int    `json:"column"`
+		Text   string `json:"text"`
+	}{
+		Module: gm.Module.Name,
+		File:   gm.File,
+		Line:   gm.Line,
+		Column: gm.Column,
+		Text:   gm.Text,
+	})
+}
+
+// Grep searches |pattern| in parallel over the files the index knows about.
+// Matches are sorted by module, file, line and column.
+func (p *Project) Grep(ctx context.Context, pattern *regexp.Regexp, options *GrepOptions) ([]*GrepMatch, error) {
+	if options == nil {
+		options = &GrepOptions{}
+	}
+
+	if !p.IsIndexed() {
+		return nil, fmt.Errorf("no modules loaded. Is the project indexed?")
+	}
+
+	modules, err := p.scopeModules(options.Modules, options.Plugins)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []*ModuleFile
+	for _, module := range modules {
+		for _, file := range module.Files {
+			files = append(files, &ModuleFile{Module: module, Path: file})
+		}
+
+		if !options.IncludeUHT {
+			continue
+		}
+
+		uhtFiles, err := module.LoadUHTFiles(options.Platform, false)
+		if err != nil {
+			return nil, fmt.Errorf("loading uht files for module %q: %w", module.Name, err)
+		}
+
+		for _, file := range uhtFiles {
+			files = append(files, &ModuleFile{Module: module, Path: file})
+		}
+	}
+
+	matches, err := scanModuleFiles(ctx, files, func(mf *ModuleFile) ([]*GrepMatch, error) {
+		return grepFile(mf, pattern)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("searching files: %w", err)
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.Module.Name != b.Module.Name {
+			return a.Module.Name < b.Module.Name
+		}
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+
+	return matches, nil
+}
+
+// scopeModules returns the modules that match the given names and plugins.
+// Empty filters match everything. Unknown names are an error, as they are normally typos.
+func (p *Project) scopeModules(moduleNames, pluginNames []string) ([]*Module, error) {
+	for _, name := range moduleNames {
+		if _, ok := p.Modules[name]; !ok {
+			return nil, fmt.Errorf("module %q not found", name)
+		}
+	}
+
+	for _, name := range pluginNames {
+		if _, ok := p.Plugins[name]; !ok {
+			return nil, fmt.Errorf("plugin %q not found", name)
+		}
+	}
+
+	var result []*Module
+	for _, module := range p.Modules {
+		if len(moduleNames) > 0 && !slices.Contains(moduleNames, module.Name) {
+			continue
+		}
+
+		if len(pluginNames) > 0 && (module.Plugin == nil || !slices.Contains(pluginNames, module.Plugin.Name)) {
+			continue
+		}
+
+		result = append(result, module)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result, nil
+}
+
+func grepFile(mf *ModuleFile, pattern *regexp.Regexp) ([]*GrepMatch, error) {
+	data, err := os.ReadFile(mf.Path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %q: %w", mf.Path, err)
+	}
+
+	// Skip binary files (eg. icons within the module resources).
+	sniff := data
+	if len(sniff) > kGrepBinarySniffSize {
+		sniff = sniff[:kGrepBinarySniffSize]
+	}
+	if bytes.IndexByte(sniff, 0) >= 0 {
+		return nil, nil
+	}
+
+	var matches []*GrepMatch
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), kGrepMaxLineSize)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := bytes.TrimSuffix(scanner.Bytes(), []byte("\r"))
+
+		for _, loc := range pattern.FindAllIndex(text, -1) {
+			matches = append(matches, &GrepMatch{
+				Module: mf.Module,
+				File:   mf.Path,
+				Line:   line,
+				Column: loc[0] + 1,
+				Text:   string(text),
+			})
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("scanning %q: %w", mf.Path, err)
+	}
+
+	return matches, nil
+}
@@ -0,0 +1,167 @@
+package unreal
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// ClassNode is a class within a ClassHierarchy.
+// Classes that are not declared within the project (eg. engine classes) have no Type nor File.
+type ClassNode struct {
+	Name string
+	Type *ReflectedType
+	// File is the header that declares the class, attributed to its module.
+	File     *File
+	Children []*ClassNode
+}
+
+// MarshalJSON outputs the node in a compact form, as |Type| and |File| point back to the project.
+func (cn *ClassNode) MarshalJSON() ([]byte, error) {
+	out := struct {
+		Name     string       `json:"name"`
+		Module   string       `json:"module,omitempty"`
+		Header   string       `json:"header,omitempty"`
+		Line     int          `json:"line,omitempty"`
+		Children []*ClassNode `json:"children,omitempty"`
+	}{
+		Name:     cn.Name,
+		Children: cn.Children,
+	}
+
+	if cn.File != nil {
+		out.Header = cn.File.Path
+		if cn.File.Module != nil {
+			out.Module = cn.File.Module.Name
+		}
+	}
+	if cn.Type != nil {
+		out.Line = cn.Type.Line
+	}
+
+	return json.Marshal(out)
+}
+
+// ClassHierarchy is the view of the class hierarchy around a particular class.
+type ClassHierarchy struct {
+	// Class is the queried class. Its children are all the descendants.
+	Class *ClassNode `json:"class"`
+	// Ancestors goes from the direct parent upwards. The last one is the first class that is not
+	// declared within the project, if any.
+	Ancestors []*ClassNode `json:"ancestors"`
+}
+
+// ClassHierarchy calculates the ancestors and descendants of |className| across all the indexed
+// reflected types. Requires |IndexReflectedTypes| to have been called.
+func (p *Project) ClassHierarchy(className string) (*ClassHierarchy, error) {
+	// Map each parent to the classes that directly derive from it.
+	children := map[string][]*ReflectedType{}
+	for _, rt := range p.reflectedTypes {
+		if rt.Kind != ReflectedKind_Class && rt.Kind != ReflectedKind_Interface {
+			continue
+		}
+
+		if rt.Parent != "" {
+			children[rt.Parent] = append(children[rt.Parent], rt)
+		}
+	}
+
+	if _, ok := p.FindReflectedType(className); !ok {
+		if _, ok := children[className]; !ok {
+			return nil, fmt.Errorf("class %q is not declared nor derived from within the project", className)
+		}
+	}
+
+	class, err := p.newClassNode(className)
+	if err != nil {
+		return nil, err
+	}
+
+	// Descendants.
+	visited := map[string]struct{}{className: {}}
+	var addChildren func(node *ClassNode) error
+	addChildren = func(node *ClassNode) error {
+		for _, child := range children[node.Name] {
+			// Guard against malformed cycles.
+			if _, ok := visited[child.Name]; ok {
+				continue
+			}
+			visited[child.Name] = struct{}{}
+
+			childNode, err := p.newClassNode(child.Name)
+			if err != nil {
+				return err
+			}
+
+			if err := addChildren(childNode); err != nil {
+				return err
+			}
+			node.Children = append(node.Children, childNode)
+		}
+
+		sort.Slice(node.Children, func(i, j int) bool {
+			return node.Children[i].Name < node.Children[j].Name
+		})
+		return nil
+	}
+	if err := addChildren(class); err != nil {
+		return nil, err
+	}
+
+	// Ancestors.
+	var ancestors []*ClassNode
+	for current := class; current.Type != nil && current.Type.Parent != ""; {
+		if _, ok := visited[current.Type.Parent]; ok {
+			break
+		}
+		visited[current.Type.Parent] = struct{}{}
+
+		parent, err := p.newClassNode(current.Type.Parent)
+		if err != nil {
+			return nil, err
+		}
+
+		ancestors = append(ancestors, parent)
+		current = parent
+	}
+
+	return &ClassHierarchy{
+		Class:     class,
+		Ancestors: ancestors,
+	}, nil
+}
+
+// Descendants returns all the descendants of the class in depth-first order.
+func (ch *ClassHierarchy) Descendants() []*ClassNode {
+	var result []*ClassNode
+	var visit func(node *ClassNode)
+	visit = func(node *ClassNode) {
+		for _, child := range node.Children {
+			result = append(result, child)
+			visit(child)
+		}
+	}
+	visit(ch.Class)
+
+	return result
+}
+
+func (p *Project) newClassNode(name string) (*ClassNode, error) {
+	node := &ClassNode{
+		Name: name,
+	}
+
+	rt, ok := p.FindReflectedType(name)
+	if !ok {
+		return node, nil
+	}
+	node.Type = rt
+
+	file, err := p.NewFile(rt.File)
+	if err != nil {
+		return nil, fmt.Errorf("attributing header for class %q: %w", name, err)
+	}
+	node.File = file
+
+	return node, nil
+}
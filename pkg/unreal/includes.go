@@ -0,0 +1,347 @@
+package unreal
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// gIncludeSourceExtensions are the (lowercase) extensions of the files whose includes get parsed.
+var gIncludeSourceExtensions = []string{
+	".h",
+	".hpp",
+	".inl",
+	".cpp",
+	".c",
+	".cc",
+}
+
+var gIncludeRegex = regexp.MustCompile(`(?m)^[ \t]*#[ \t]*include[ \t]*([<"])([^>"\n]+)[>"]`)
+
+// gIncludeHeaderExtensions are the (lowercase) extensions of the files that get included.
+var gIncludeHeaderExtensions = []string{
+	".h",
+	".hpp",
+	".inl",
+}
+
+// IncludeDirective is a single #include found within a file.
+type IncludeDirective struct {
+	// Path is the include as written in the source.
+	Path   string `json:"path"`
+	Line   int    `json:"line"`
+	System bool   `json:"system"`
+	// Resolved is the file within the project the include points to. Empty if it could not be
+	// resolved (eg. engine headers when the engine is not indexed).
+	Resolved string `json:"resolved,omitempty"`
+	// OutsideIncludePaths is set when the include could only be resolved by searching modules that
+	// are not reachable from the including module, meaning UBT would not find it either.
+	OutsideIncludePaths bool `json:"outside_include_paths,omitempty"`
+}
+
+// IncludeGraph holds the resolved #include directives of all the indexed files.
+type IncludeGraph struct {
+	// Includes maps each file to the includes it has, in source order.
+	Includes map[string][]*IncludeDirective
+
+	// includers maps each resolved file to the files that directly include it.
+	includers map[string][]string
+}
+
+// IndexIncludes parses the #include directives of every indexed file and resolves them against the
+// include directories UBT would give each module. Requires |IndexModules| to have been called.
+func (p *Project) IndexIncludes(ctx context.Context) error {
+	if !p.IsIndexed() {
+		return fmt.Errorf("no modules loaded. Is the project indexed?")
+	}
+
+	// Files are resolved case insensitively, as most Unreal development happens on Windows.
+	knownFiles := map[string]string{}
+	for _, module := range p.Modules {
+		for _, file := range module.Files {
+			knownFiles[includeKey(file)] = file
+		}
+	}
+	for _, header := range p.engineHeaders() {
+		knownFiles[includeKey(header)] = header
+	}
+
+	// Calculate the include directories of each module only once.
+	includeDirs := map[string][]string{}
+	for _, module := range p.Modules {
+		includeDirs[module.Name] = p.moduleIncludeDirs(module)
+	}
+
+	// When an include cannot be resolved through the module include directories, we still want to
+	// know where it points to, so that the checks can explain what dependency is missing.
+	// Modules are sorted so that the resolution is deterministic.
+	sortedModules := p.sortedModules()
+	var fallbackDirs []string
+	for _, module := range sortedModules {
+		fallbackDirs = append(fallbackDirs, module.PublicDir(), module.ClassesDir())
+	}
+	for _, module := range sortedModules {
+		fallbackDirs = append(fallbackDirs, module.BaseDir, module.PrivateDir())
+	}
+
+	type fileIncludes struct {
+		path     string
+		includes []*IncludeDirective
+	}
+
+	files := p.moduleFiles(func(path string) bool {
+		return hasAnySuffix(strings.ToLower(path), gIncludeSourceExtensions)
+	})
+
+	results, err := scanModuleFiles(ctx, files, func(mf *ModuleFile) ([]*fileIncludes, error) {
+		data, err := os.ReadFile(mf.Path)
+		if err != nil {
+			return nil, fmt.Errorf("reading %q: %w", mf.Path, err)
+		}
+
+		includes := parseIncludes(data)
+		for _, include := range includes {
+			include.Resolved = resolveInclude(mf.Path, include, includeDirs[mf.Module.Name], knownFiles)
+			if include.Resolved == "" {
+				include.Resolved = resolveInclude(mf.Path, include, fallbackDirs, knownFiles)
+				if include.Resolved == "" && p.Engine != nil {
+					include.Resolved, _ = p.Engine.FindPublicHeader(include.Path)
+				}
+				include.OutsideIncludePaths = include.Resolved != ""
+			}
+		}
+
+		return []*fileIncludes{{path: mf.Path, includes: includes}}, nil
+	})
+	if err != nil {
+		return fmt.Errorf("scanning includes: %w", err)
+	}
+
+	graph := &IncludeGraph{
+		Includes:  make(map[string][]*IncludeDirective, len(results)),
+		includers: map[string][]string{},
+	}
+	for _, result := range results {
+		graph.Includes[result.path] = result.includes
+		for _, include := range result.includes {
+			if include.Resolved != "" {
+				graph.includers[include.Resolved] = append(graph.includers[include.Resolved], result.path)
+			}
+		}
+	}
+
+	for file, includers := range graph.includers {
+		graph.includers[file] = sortedUnique(includers)
+	}
+
+	p.includeGraph = graph
+	return nil
+}
+
+// engineHeaders returns all the headers of the indexed engine modules. Empty if the engine is not
+// indexed.
+func (p *Project) engineHeaders() []string {
+	if p.Engine == nil {
+		return nil
+	}
+
+	var headers []string
+	for _, module := range p.Engine.Modules {
+		for _, file := range module.Files {
+			if hasAnySuffix(strings.ToLower(file), gIncludeHeaderExtensions) {
+				headers = append(headers, file)
+			}
+		}
+	}
+	return headers
+}
+
+// IncludeGraph returns the graph calculated by |IndexIncludes|.
+func (p *Project) IncludeGraph() *IncludeGraph {
+	return p.includeGraph
+}
+
+// moduleIncludeDirs returns the directories |module| can include from: its own directories plus
+// the public directories of its dependencies. Public dependencies are followed transitively, as UBT
+// propagates them. Engine dependencies are only followed if the engine is indexed.
+func (p *Project) moduleIncludeDirs(module *Module) []string {
+	dirs := []string{
+		module.BaseDir,
+		module.PublicDir(),
+		module.PrivateDir(),
+		module.ClassesDir(),
+	}
+
+	visited := map[string]struct{}{module.Name: {}}
+	var queue []string
+	if module.Rules != nil {
+		queue = append(queue, module.Rules.AllDependencies()...)
+	}
+
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+
+		if _, ok := visited[name]; ok {
+			continue
+		}
+		visited[name] = struct{}{}
+
+		dep, ok := p.LookupModule(name)
+		if !ok {
+			continue
+		}
+
+		dirs = append(dirs, dep.PublicDir(), dep.ClassesDir())
+		if dep.Rules != nil {
+			queue = append(queue, dep.Rules.PublicDependencyNames()...)
+		}
+	}
+
+	return dirs
+}
+
+// parseIncludes finds all the #include directives within the content of a C++ file.
+func parseIncludes(data []byte) []*IncludeDirective {
+	text := string(stripCppComments(data))
+	lines := newLineIndex(data)
+
+	var includes []*IncludeDirective
+	for _, match := range gIncludeRegex.FindAllStringSubmatchIndex(text, -1) {
+		includes = append(includes, &IncludeDirective{
+			Path:   strings.TrimSpace(text[match[4]:match[5]]),
+			Line:   lines.Line(match[0]),
+			System: text[match[2]:match[3]] == "<",
+		})
+	}
+
+	return includes
+}
+
+// resolveInclude searches |include| the way the compiler would: quoted includes first check the
+// directory of the including file, then the include directories are checked in order.
+func resolveInclude(from string, include *IncludeDirective, includeDirs []string, knownFiles map[string]string) string {
+	var candidates []string
+	if !include.System {
+		candidates = append(candidates, filepath.Dir(from))
+	}
+	candidates = append(candidates, includeDirs...)
+
+	for _, dir := range candidates {
+		if resolved, ok := knownFiles[includeKey(filepath.Join(dir, include.Path))]; ok {
+			return resolved
+		}
+	}
+
+	return ""
+}
+
+func includeKey(path string) string {
+	return strings.ToLower(filepath.Clean(path))
+}
+
+// FindFile searches the graph for |path|. It accepts an exact path or an unambiguous suffix of one
+// (eg. "MyActor.h" or "Public/MyActor.h").
+func (ig *IncludeGraph) FindFile(path string) (string, error) {
+	if _, ok := ig.Includes[path]; ok {
+		return path, nil
+	}
+
+	suffix := includeKey(path)
+	var matches []string
+	for file := range ig.Includes {
+		key := includeKey(file)
+		if key == suffix || strings.HasSuffix(key, string(filepath.Separator)+suffix) {
+			matches = append(matches, file)
+		}
+	}
+	sort.Strings(matches)
+
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("file %q is not indexed", path)
+	case 1:
+		return matches[0], nil
+	default:
+		return "", fmt.Errorf("file %q is ambiguous: %s", path, strings.Join(matches, ", "))
+	}
+}
+
+// DirectIncludes returns the resolved files |file| includes directly, sorted.
+func (ig *IncludeGraph) DirectIncludes(file string) []string {
+	var result []string
+	for _, include := range ig.Includes[file] {
+		if include.Resolved != "" {
+			result = append(result, include.Resolved)
+		}
+	}
+	return sortedUnique(result)
+}
+
+// TransitiveIncludes returns every resolved file |file| ends up including, sorted.
+func (ig *IncludeGraph) TransitiveIncludes(file string) []string {
+	return ig.walk(file, ig.DirectIncludes)
+}
+
+// Includers returns the files that directly include |file|, sorted.
+func (ig *IncludeGraph) Includers(file string) []string {
+	return ig.includers[file]
+}
+
+// TransitiveIncluders returns every file that ends up including |file|, sorted.
+func (ig *IncludeGraph) TransitiveIncluders(file string) []string {
+	return ig.walk(file, ig.Includers)
+}
+
+// Unresolved returns the includes of |file| that could not be resolved within the project.
+func (ig *IncludeGraph) Unresolved(file string) []*IncludeDirective {
+	var result []*IncludeDirective
+	for _, include := range ig.Includes[file] {
+		if include.Resolved == "" {
+			result = append(result, include)
+		}
+	}
+	return result
+}
+
+// OutsideIncludePaths returns the includes of |file| that only resolve through modules the file
+// cannot reach. See |IncludeDirective.OutsideIncludePaths|.
+func (ig *IncludeGraph) OutsideIncludePaths(file string) []*IncludeDirective {
+	var result []*IncludeDirective
+	for _, include := range ig.Includes[file] {
+		if include.OutsideIncludePaths {
+			result = append(result, include)
+		}
+	}
+	return result
+}
+
+// walk does a breadth first traversal from |file| using |next| to find the neighbours.
+// |file| itself is not part of the result.
+func (ig *IncludeGraph) walk(file string, next func(string) []string) []string {
+	visited := map[string]struct{}{file: {}}
+	queue := []string{file}
+
+	var result []string
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, neighbour := range next(current) {
+			if _, ok := visited[neighbour]; ok {
+				continue
+			}
+			visited[neighbour] = struct{}{}
+
+			result = append(result, neighbour)
+			queue = append(queue, neighbour)
+		}
+	}
+
+	sort.Strings(result)
+	return result
+}
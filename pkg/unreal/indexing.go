@@ -8,7 +8,6 @@ import (
 	"path/filepath"
 	"slices"
 	"sort"
-	"strings"
 	"sync"
 
 	"golang.org/x/sync/errgroup"
@@ -17,6 +16,15 @@ import (
 // collectModules scans the whole |Source| directory of an unreal project in a parallel fashion.
 // Indexes all the files within a project, for faster in memory searching afterwards.
 func collectModules(ctx context.Context, sourceDir string) (map[string]*Module, error) {
+	return collectModulesWithDuplicates(ctx, sourceDir, func(existing, duplicate *Module) error {
+		return fmt.Errorf("module %q found more than once", duplicate.Name)
+	})
+}
+
+// collectModulesWithDuplicates is |collectModules|, but lets the caller decide what to do when two
+// modules have the same name. If |onDuplicate| does not fail, the module whose build file sorts
+// first is kept, so that the result is deterministic.
+func collectModulesWithDuplicates(ctx context.Context, sourceDir string, onDuplicate func(existing, duplicate *Module) error) (map[string]*Module, error) {
 	// collect all the files in the unreal project.
 	result, err := collectFiles(ctx, sourceDir)
 	if err != nil {
@@ -75,7 +83,7 @@ func collectModules(ctx context.Context, sourceDir string) (map[string]*Module,
 					// Search backwards from the build file index.
 					for i := index - 1; i >= 0; i-- {
 						file := result.allFiles[i]
-						if strings.Contains(file, baseDir) {
+						if isWithinDir(file, baseDir) {
 							moduleFiles = append(moduleFiles, file)
 							continue
 						}
@@ -87,7 +95,7 @@ func collectModules(ctx context.Context, sourceDir string) (map[string]*Module,
 					// Search forward from the build file index.
 					for i := index + 1; i < len(result.allFiles); i++ {
 						file := result.allFiles[i]
-						if strings.Contains(file, baseDir) {
+						if isWithinDir(file, baseDir) {
 							moduleFiles = append(moduleFiles, file)
 							continue
 						}
@@ -105,6 +113,12 @@ func collectModules(ctx context.Context, sourceDir string) (map[string]*Module,
 						return fmt.Errorf("creating unreal module %q: %w", bfd.ModuleName, err)
 					}
 
+					rules, err := parseModuleRules(bfd.Path)
+					if err != nil {
+						return fmt.Errorf("parsing module rules for %q: %w", bfd.ModuleName, err)
+					}
+					um.Rules = rules
+
 					select {
 					case modulesCh <- um:
 						continue
@@ -130,8 +144,14 @@ func collectModules(ctx context.Context, sourceDir string) (map[string]*Module,
 	{
 		g.Go(func() error {
 			for module := range modulesCh {
-				if _, ok := modules[module.Name]; ok {
-					return fmt.Errorf("module %q found more than once", module.Name)
+				if existing, ok := modules[module.Name]; ok {
+					if err := onDuplicate(existing, module); err != nil {
+						return err
+					}
+
+					if existing.BuildFile < module.BuildFile {
+						continue
+					}
 				}
 
 				modules[module.Name] = module
@@ -0,0 +1,169 @@
+package unreal
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+const (
+	DiagnosticCode_PrivateHeaderIncluded = "private-header-included"
+	DiagnosticCode_SourceInPublic        = "source-in-public"
+	DiagnosticCode_PublicIncludesPrivate = "public-includes-private"
+	DiagnosticCode_APIMacroMismatch      = "api-macro-mismatch"
+)
+
+const (
+	// Prefixes of |File.ModulePath| for the module layout directories.
+	kLayoutPublicPrefix  = "Public/"
+	kLayoutPrivatePrefix = "Private/"
+)
+
+var (
+	// gAPIDeclarationRegex matches the macro in the export position of a declaration: "class FOO_API UFoo".
+	gAPIDeclarationRegex = regexp.MustCompile(`\b(?:class|struct)\s+(\w+)\s+\w`)
+
+	// gMacroAliasRegex matches the defines that alias another macro: "#define UE_API FOO_API".
+	gMacroAliasRegex = regexp.MustCompile(`(?m)^[ \t]*#[ \t]*define[ \t]+(\w+)[ \t]+(\w+)[ \t]*$`)
+)
+
+// LintLayout checks that the modules follow the Unreal Public/Private layout conventions:
+//
+//   - Headers in Private/ are not included from other modules.
+//   - There are no .cpp files in Public/.
+//   - Public headers do not include private headers.
+//   - The *_API export macros match the module they are used in.
+//
+// These mistakes are normally hidden until a modular (non-unity) build links.
+func (p *Project) LintLayout(ctx context.Context) ([]*Diagnostic, error) {
+	if p.includeGraph == nil {
+		if err := p.IndexIncludes(ctx); err != nil {
+			return nil, fmt.Errorf("indexing includes: %w", err)
+		}
+	}
+
+	fileModules := p.fileModules()
+
+	var diagnostics []*Diagnostic
+	for _, module := range p.Modules {
+		expectedMacro := strings.ToUpper(module.Name) + "_API"
+
+		for _, path := range module.Files {
+			file := &File{Path: path, Module: module}
+			modulePath := file.ModulePath()
+			isPublic := strings.HasPrefix(modulePath, kLayoutPublicPrefix)
+			lower := strings.ToLower(path)
+
+			if isPublic && hasAnySuffix(lower, gCompdbSourceExtensions) {
+				diagnostics = append(diagnostics, &Diagnostic{
+					File:     path,
+					Severity: DiagnosticSeverity_Warning,
+					Code:     DiagnosticCode_SourceInPublic,
+					Message:  fmt.Sprintf("source file in the Public directory of module %s. Move it to Private/", module.Name),
+				})
+			}
+
+			for _, include := range p.includeGraph.Includes[path] {
+				if include.Resolved == "" {
+					continue
+				}
+
+				owner, ok := fileModules[include.Resolved]
+				if !ok {
+					continue
+				}
+
+				included := &File{Path: include.Resolved, Module: owner}
+				if !strings.HasPrefix(included.ModulePath(), kLayoutPrivatePrefix) {
+					continue
+				}
+
+				if owner != module {
+					diagnostics = append(diagnostics, &Diagnostic{
+						File:     path,
+						Line:     include.Line,
+						Severity: DiagnosticSeverity_Error,
+						Code:     DiagnosticCode_PrivateHeaderIncluded,
+						Message:  fmt.Sprintf("%q is a private header of module %s", include.Path, owner.Name),
+					})
+				} else if isPublic {
+					diagnostics = append(diagnostics, &Diagnostic{
+						File:     path,
+						Line:     include.Line,
+						Severity: DiagnosticSeverity_Error,
+						Code:     DiagnosticCode_PublicIncludesPrivate,
+						Message:  fmt.Sprintf("public header includes private header %q", include.Path),
+					})
+				}
+			}
+
+			if strings.HasSuffix(lower, ".h") {
+				apiDiagnostics, err := lintAPIMacros(path, expectedMacro)
+				if err != nil {
+					return nil, err
+				}
+				diagnostics = append(diagnostics, apiDiagnostics...)
+			}
+		}
+	}
+
+	SortDiagnostics(diagnostics)
+	return diagnostics, nil
+}
+
+// lintAPIMacros reports the class and struct declarations within the header at |path| exported with
+// a macro other than |expected|. Macros the header defines as another one (eg. UE_API) are resolved.
+func lintAPIMacros(path, expected string) ([]*Diagnostic, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %q: %w", path, err)
+	}
+
+	text := string(stripCppComments(data))
+	lines := newLineIndex(data)
+
+	aliases := map[string]string{}
+	for _, match := range gMacroAliasRegex.FindAllStringSubmatch(text, -1) {
+		aliases[match[1]] = match[2]
+	}
+
+	var diagnostics []*Diagnostic
+	for _, match := range gAPIDeclarationRegex.FindAllStringSubmatchIndex(text, -1) {
+		macro := text[match[2]:match[3]]
+		resolved := resolveMacroAlias(macro, aliases)
+		if !strings.HasSuffix(resolved, "_API") || resolved == expected {
+			continue
+		}
+
+		name := macro
+		if resolved != macro {
+			name = fmt.Sprintf("%s (%s)", macro, resolved)
+		}
+
+		diagnostics = append(diagnostics, &Diagnostic{
+			File:     path,
+			Line:     lines.Line(match[2]),
+			Column:   lines.Column(match[2]),
+			Severity: DiagnosticSeverity_Error,
+			Code:     DiagnosticCode_APIMacroMismatch,
+			Message:  fmt.Sprintf("export macro %s does not match the module (expected %s)", name, expected),
+		})
+	}
+
+	return diagnostics, nil
+}
+
+// resolveMacroAlias follows |macro| through |aliases| until it is not an alias anymore.
+func resolveMacroAlias(macro string, aliases map[string]string) string {
+	// Bounded, so that circular defines do not loop forever.
+	for i := 0; i <= len(aliases); i++ {
+		target, ok := aliases[macro]
+		if !ok {
+			break
+		}
+		macro = target
+	}
+	return macro
+}
@@ -0,0 +1,459 @@
+package unreal
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// kLogFollowPollInterval is how often |FollowLog| checks for new output.
+	kLogFollowPollInterval = 250 * time.Millisecond
+)
+
+var (
+	// gLogLineRegex matches an Unreal log line:
+	// "[2024.01.02-10.11.12:345][ 12]LogNet: Warning: Connection lost".
+	// The timestamp and frame are missing for the lines logged before the engine is initialized.
+	gLogLineRegex = regexp.MustCompile(
+		`^(?:\[(\d{4}\.\d{2}\.\d{2}-\d{2}\.\d{2}\.\d{2}:\d{3})\]\[\s*(\d+)\])?(\w+): (?:(Fatal|Error|Warning|Display|Log|Verbose|VeryVerbose): )?(.*)$`)
+)
+
+// LogVerbosity is the verbosity a log line was logged with (ELogVerbosity).
+type LogVerbosity string
+
+const (
+	LogVerbosity_Fatal       LogVerbosity = "Fatal"
+	LogVerbosity_Error       LogVerbosity = "Error"
+	LogVerbosity_Warning     LogVerbosity = "Warning"
+	LogVerbosity_Display     LogVerbosity = "Display"
+	LogVerbosity_Log         LogVerbosity = "Log"
+	LogVerbosity_Verbose     LogVerbosity = "Verbose"
+	LogVerbosity_VeryVerbose LogVerbosity = "VeryVerbose"
+)
+
+// gLogVerbosities are sorted from the most to the least severe.
+var gLogVerbosities = []LogVerbosity{
+	LogVerbosity_Fatal,
+	LogVerbosity_Error,
+	LogVerbosity_Warning,
+	LogVerbosity_Display,
+	LogVerbosity_Log,
+	LogVerbosity_Verbose,
+	LogVerbosity_VeryVerbose,
+}
+
+// NewLogVerbosity normalizes a verbosity given by the user (eg. "warning" -> "Warning").
+func NewLogVerbosity(id string) (LogVerbosity, error) {
+	for _, verbosity := range gLogVerbosities {
+		if strings.EqualFold(id, string(verbosity)) {
+			return verbosity, nil
+		}
+	}
+	return "", fmt.Errorf("unrecognized log verbosity %q", id)
+}
+
+// AtLeast returns whether |lv| is as severe as |other| or more.
+func (lv LogVerbosity) AtLeast(other LogVerbosity) bool {
+	return lv.rank() <= other.rank()
+}
+
+func (lv LogVerbosity) rank() int {
+	for i, verbosity := range gLogVerbosities {
+		if verbosity == lv {
+			return i
+		}
+	}
+	return len(gLogVerbosities)
+}
+
+// LogRecord is a single log entry. Lines that do not start a new entry (eg. callstacks or multi-line
+// messages) are appended to the previous one.
+type LogRecord struct {
+	File string `json:"file"`
+	// Line is the line within |File| where the record starts (1-based). Zero if unknown, as happens
+	// when following a log from its end.
+	Line int `json:"line,omitempty"`
+	// Time is zero for the records logged before the engine started timestamping them.
+	Time      time.Time    `json:"time,omitempty"`
+	Frame     int          `json:"frame"`
+	Category  string       `json:"category"`
+	Verbosity LogVerbosity `json:"verbosity"`
+	Message   string       `json:"message"`
+
+	// Raw are the lines of the record as they appear in the file.
+	Raw string `json:"-"`
+}
+
+// LogFilter selects log records. Empty fields match everything.
+type LogFilter struct {
+	// Categories are matched case insensitively.
+	Categories []string
+	// MinVerbosity keeps the records at least this severe.
+	MinVerbosity LogVerbosity
+	// Since keeps the records logged at or after this time. Records without time are dropped.
+	Since time.Time
+}
+
+// Matches returns whether |record| passes the filter.
+func (lf *LogFilter) Matches(record *LogRecord) bool {
+	if lf == nil {
+		return true
+	}
+
+	if len(lf.Categories) > 0 {
+		found := false
+		for _, category := range lf.Categories {
+			if strings.EqualFold(category, record.Category) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if lf.MinVerbosity != "" && !record.Verbosity.AtLeast(lf.MinVerbosity) {
+		return false
+	}
+
+	if !lf.Since.IsZero() && (record.Time.IsZero() || record.Time.Before(lf.Since)) {
+		return false
+	}
+
+	return true
+}
+
+// LogsDir is where Unreal writes the logs of the project.
+func (p *Project) LogsDir() string {
+	return filepath.Join(p.ProjectDir(), "Saved", "Logs")
+}
+
+// LogFiles returns the .log files within |LogsDir|, from the oldest to the most recently modified.
+// The current log is <Project>.log, while the previous runs are rotated to <Project>-backup-*.log.
+func (p *Project) LogFiles() ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(p.LogsDir(), "*.log"))
+	if err != nil {
+		return nil, fmt.Errorf("globbing logs: %w", err)
+	}
+
+	modTimes := map[string]time.Time{}
+	for _, match := range matches {
+		info, err := os.Stat(match)
+		if err != nil {
+			return nil, fmt.Errorf("statting %q: %w", match, err)
+		}
+		modTimes[match] = info.ModTime()
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := modTimes[matches[i]], modTimes[matches[j]]
+		if !a.Equal(b) {
+			return a.Before(b)
+		}
+		return matches[i] < matches[j]
+	})
+
+	return matches, nil
+}
+
+// LatestLogFile returns the most recently modified log.
+func (p *Project) LatestLogFile() (string, error) {
+	logs, err := p.LogFiles()
+	if err != nil {
+		return "", err
+	}
+	if len(logs) == 0 {
+		return "", fmt.Errorf("no logs found in %q", p.LogsDir())
+	}
+
+	return logs[len(logs)-1], nil
+}
+
+// ParseLogFile calls |fn| with every record of the log at |path| that passes |filter|, in order.
+// The file is streamed, so big logs do not need to fit in memory.
+func ParseLogFile(path string, filter *LogFilter, fn func(record *LogRecord) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("opening %q: %w", path, err)
+	}
+	defer file.Close()
+
+	parser := newLogParser(path, filter, fn)
+	reader := bufio.NewReaderSize(file, 1024*1024)
+	for {
+		line, err := reader.ReadString('\n')
+		if len(line) > 0 {
+			if err := parser.feed(line); err != nil {
+				return err
+			}
+		}
+
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("reading %q: %w", path, err)
+		}
+	}
+
+	return parser.flush()
+}
+
+// FollowLog waits for new records in the latest log and calls |fn| with the ones that pass |filter|,
+// until |ctx| is cancelled. If |fromStart| is set, the existing records are reported first.
+// Records are reported once the next one starts (or once following stops), as the continuation lines
+// of a record can be written separately.
+// When the log rotates (a new run of the project renames it to a backup and starts a new one, or the
+// file is truncated) the new log is followed from its start. Other logs being written at the same
+// time (eg. a second instance writing <Project>_2.log) are not followed.
+func (p *Project) FollowLog(ctx context.Context, fromStart bool, filter *LogFilter, fn func(record *LogRecord) error) error {
+	path, err := p.LatestLogFile()
+	if err != nil {
+		return err
+	}
+
+	// Where each of the logs was left, so that coming back to one resumes it instead of replaying it.
+	var followed []*followedLog
+	for {
+		rotated, err := p.followLogFile(ctx, path, fromStart, &followed, filter, fn)
+		if err != nil {
+			return err
+		}
+		if rotated == "" {
+			return nil
+		}
+
+		path = rotated
+		fromStart = true
+	}
+}
+
+// followedLog is a log file |FollowLog| already read up to |offset|.
+type followedLog struct {
+	info   os.FileInfo
+	offset int64
+}
+
+// findFollowedLog returns the entry of |followed| for the file |info|, or nil if it was not followed.
+func findFollowedLog(followed []*followedLog, info os.FileInfo) *followedLog {
+	for _, log := range followed {
+		if os.SameFile(log.info, info) {
+			return log
+		}
+	}
+	return nil
+}
+
+// followLogFile follows the log at |path| until |ctx| is cancelled, in which case it returns an empty
+// path, or until the log rotates, in which case it returns the log to follow next. A log already in
+// |followed| is resumed where it was left. Otherwise it is read from the start if |fromStart| is set.
+func (p *Project) followLogFile(ctx context.Context, path string, fromStart bool, followed *[]*followedLog,
+	filter *LogFilter, fn func(record *LogRecord) error) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("opening %q: %w", path, err)
+	}
+	defer file.Close()
+
+	opened, err := file.Stat()
+	if err != nil {
+		return "", fmt.Errorf("statting %q: %w", path, err)
+	}
+
+	log := findFollowedLog(*followed, opened)
+	if log == nil {
+		log = &followedLog{info: opened}
+		if !fromStart {
+			log.offset = opened.Size()
+		}
+		*followed = append(*followed, log)
+	}
+
+	offset := log.offset
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return "", fmt.Errorf("seeking %q: %w", path, err)
+	}
+
+	parser := newLogParser(path, filter, fn)
+	parser.countLines = offset == 0
+	reader := bufio.NewReaderSize(file, 1024*1024)
+	var partial string
+	for {
+		line, err := reader.ReadString('\n')
+		offset += int64(len(line))
+		if err != nil && err != io.EOF {
+			return "", fmt.Errorf("reading %q: %w", path, err)
+		}
+
+		// Complete lines get parsed. A partial one waits until the rest of it is written.
+		if err == nil {
+			if err := parser.feed(partial + line); err != nil {
+				return "", err
+			}
+			partial = ""
+			log.offset = offset
+			continue
+		}
+		partial += line
+
+		// There is nothing else to read for now. The pending record is kept, as the rest of a
+		// multi-line record (eg. a callstack) can come in a later write. It is only complete once the
+		// next record starts, or once we stop reading this log.
+		select {
+		case <-ctx.Done():
+			return "", parser.flush()
+		case <-time.After(kLogFollowPollInterval):
+		}
+
+		// A truncated log is read again from its start.
+		if info, err := file.Stat(); err != nil {
+			return "", fmt.Errorf("statting %q: %w", path, err)
+		} else if info.Size() < offset {
+			log.offset = 0
+			return path, parser.flush()
+		}
+
+		if rotated, err := p.logRotation(path, opened); err != nil {
+			return "", err
+		} else if rotated != "" {
+			return rotated, parser.flush()
+		}
+	}
+}
+
+// logRotation returns the log that should be followed instead of the one at |path| (opened as
+// |opened|), or empty if there was no rotation. Logs only rotate once a new file takes the place of the
+// one we have open. Until then, a log renamed away (to a backup) keeps being followed under its new
+// name, as the run might still be writing it.
+func (p *Project) logRotation(path string, opened os.FileInfo) (string, error) {
+	current, err := os.Stat(path)
+	if err != nil {
+		// The log was renamed to a backup and the new one is not there yet.
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("statting %q: %w", path, err)
+	}
+
+	if !os.SameFile(opened, current) {
+		return path, nil
+	}
+	return "", nil
+}
+
+// logParser groups the lines of a log into records.
+type logParser struct {
+	path   string
+	filter *LogFilter
+	fn     func(record *LogRecord) error
+
+	// countLines is false when the parser does not start at the beginning of the file.
+	countLines bool
+	line       int
+	pending    *LogRecord
+}
+
+func newLogParser(path string, filter *LogFilter, fn func(record *LogRecord) error) *logParser {
+	return &logParser{path: path, filter: filter, fn: fn, countLines: true}
+}
+
+// feed processes the next line of the log.
+func (lp *logParser) feed(line string) error {
+	lp.line++
+	line = strings.TrimRight(line, "\r\n")
+
+	record := parseLogLine(line)
+	if record == nil {
+		// Continuation of the previous record.
+		if lp.pending != nil {
+			lp.pending.Message += "\n" + line
+			lp.pending.Raw += "\n" + line
+		}
+		return nil
+	}
+
+	if err := lp.flush(); err != nil {
+		return err
+	}
+
+	record.File = lp.path
+	if lp.countLines {
+		record.Line = lp.line
+	}
+	lp.pending = record
+	return nil
+}
+
+// flush reports the pending record, if any.
+func (lp *logParser) flush() error {
+	record := lp.pending
+	lp.pending = nil
+
+	if record == nil || !lp.filter.Matches(record) {
+		return nil
+	}
+	return lp.fn(record)
+}
+
+// parseLogLine returns the record |line| starts, or nil if it is not the start of a record.
+func parseLogLine(line string) *LogRecord {
+	match := gLogLineRegex.FindStringSubmatch(line)
+	if match == nil {
+		return nil
+	}
+
+	// Without the timestamp, any "Word: text" line would look like a record, so we only trust the
+	// categories that follow the Log* convention.
+	timestamp, category := match[1], match[3]
+	if timestamp == "" && !strings.HasPrefix(category, "Log") {
+		return nil
+	}
+
+	record := &LogRecord{
+		Category:  category,
+		Verbosity: LogVerbosity(match[4]),
+		Message:   match[5],
+		Raw:       line,
+	}
+	if record.Verbosity == "" {
+		record.Verbosity = LogVerbosity_Log
+	}
+
+	if timestamp != "" {
+		record.Time = parseLogTimestamp(timestamp)
+		record.Frame, _ = strconv.Atoi(match[2])
+	}
+
+	return record
+}
+
+// parseLogTimestamp parses the "2024.01.02-10.11.12:345" log timestamps, which are in UTC.
+// Returns the zero time if it is malformed.
+func parseLogTimestamp(timestamp string) time.Time {
+	index := strings.LastIndexByte(timestamp, ':')
+	if index < 0 {
+		return time.Time{}
+	}
+
+	t, err := time.Parse("2006.01.02-15.04.05", timestamp[:index])
+	if err != nil {
+		return time.Time{}
+	}
+
+	millis, err := strconv.Atoi(timestamp[index+1:])
+	if err != nil {
+		return time.Time{}
+	}
+
+	return t.Add(time.Duration(millis) * time.Millisecond)
+}
@@ -19,6 +19,10 @@ type Module struct {
 	BaseDir   string
 	BuildFile string
 	Files     []string
+	// Plugin is the plugin that holds this module. Nil for the game modules.
+	Plugin *Plugin
+	// Rules is the parsed content of the build file.
+	Rules *ModuleRules
 
 	project  *Project
 	uhtFiles map[Platform][]string
@@ -52,10 +56,31 @@ func (m *Module) String() string {
 	return fmt.Sprintf("%s (%s)", filepath.Base(m.BaseDir), m.BaseDir)
 }
 
+// PublicDir is where the module exposes its headers to other modules.
+func (m *Module) PublicDir() string {
+	return filepath.Join(m.BaseDir, "Public")
+}
+
+// PrivateDir is where the module keeps its internal headers and sources.
+func (m *Module) PrivateDir() string {
+	return filepath.Join(m.BaseDir, "Private")
+}
+
+// ClassesDir is the legacy directory for public UObject headers.
+func (m *Module) ClassesDir() string {
+	return filepath.Join(m.BaseDir, "Classes")
+}
+
 // Contains returns whether a particular path is within this module.
 // Assumes that the entry |path| has been cleaned with filepath.Clean
 func (m *Module) Contains(path string) bool {
-	return strings.HasPrefix(path, m.BaseDir)
+	return isWithinDir(path, m.BaseDir)
+}
+
+// isWithinDir returns whether |path| is |dir| or is inside of it. A plain prefix check is not enough,
+// as sibling modules often share a prefix (eg. Foo and FooEditor).
+func isWithinDir(path, dir string) bool {
+	return path == dir || strings.HasPrefix(path, dir+string(filepath.Separator))
 }
 
 // LoadUHTFiles makes this module load the UHT files associated with this module for this platform.
@@ -68,7 +93,13 @@ func (m *Module) LoadUHTFiles(platform Platform, reload bool) ([]string, error)
 	}
 
 	// If we're here we need to query the list for this module.
-	uhtDir := filepath.Join(m.project.ProjectDir(), "Intermediate", "Build", platform.String())
+	// Plugins have their own intermediate directory.
+	intermediateBase := m.project.ProjectDir()
+	if m.Plugin != nil {
+		intermediateBase = m.Plugin.BaseDir
+	}
+
+	uhtDir := filepath.Join(intermediateBase, "Intermediate", "Build", platform.String())
 	uhtDir = filepath.Join(uhtDir, "UnrealEditor", "Inc", m.Name, "UHT")
 
 	var uhtFiles []string
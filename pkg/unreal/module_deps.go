@@ -0,0 +1,170 @@
+package unreal
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+const (
+	DiagnosticCode_MissingDependency = "missing-dependency"
+	DiagnosticCode_PrivateDependency = "dependency-could-be-private"
+	DiagnosticCode_UnusedDependency  = "unused-dependency"
+)
+
+// CheckModuleDependencies cross checks the #include directives of each module against the
+// dependencies declared in its build file. It reports:
+//
+//   - Includes of headers from modules that are not listed as dependencies.
+//   - Public dependencies that are only used from private files.
+//   - Dependencies that no file of the module uses.
+//
+// Includes of engine headers are only checked if the engine is indexed (see |IndexEngine|). Engine
+// dependencies are never reported as unused, as generated code and PCHs often need them without an
+// explicit include.
+func (p *Project) CheckModuleDependencies(ctx context.Context) ([]*Diagnostic, error) {
+	if p.includeGraph == nil {
+		if err := p.IndexIncludes(ctx); err != nil {
+			return nil, fmt.Errorf("indexing includes: %w", err)
+		}
+	}
+
+	fileModules := p.fileModules()
+
+	var diagnostics []*Diagnostic
+	for _, module := range p.Modules {
+		if module.Rules == nil {
+			continue
+		}
+		dependencies := module.Rules.AllDependencies()
+
+		// Maps each used dependency to whether it's used from a public header.
+		used := map[string]bool{}
+
+		for _, file := range module.Files {
+			public := module.IsPublicFile(file)
+
+			for _, include := range p.includeGraph.Includes[file] {
+				if include.Resolved == "" {
+					continue
+				}
+
+				owner, ok := fileModules[include.Resolved]
+				if !ok || owner == module {
+					continue
+				}
+
+				used[owner.Name] = used[owner.Name] || public
+
+				if !slices.Contains(dependencies, owner.Name) {
+					diagnostics = append(diagnostics, &Diagnostic{
+						File:     file,
+						Line:     include.Line,
+						Severity: DiagnosticSeverity_Error,
+						Code:     DiagnosticCode_MissingDependency,
+						Message: fmt.Sprintf("%q belongs to module %s, which is not a dependency of %s",
+							include.Path, owner.Name, module.Name),
+					})
+				}
+			}
+		}
+
+		buildFile, err := os.ReadFile(module.BuildFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading %q: %w", module.BuildFile, err)
+		}
+
+		for _, dep := range module.Rules.PublicDependencies {
+			if _, ok := p.Modules[dep]; !ok {
+				continue
+			}
+
+			usedFromPublic, isUsed := used[dep]
+			if isUsed && !usedFromPublic {
+				diagnostics = append(diagnostics, &Diagnostic{
+					File:     module.BuildFile,
+					Line:     findQuotedLine(buildFile, dep),
+					Severity: DiagnosticSeverity_Warning,
+					Code:     DiagnosticCode_PrivateDependency,
+					Message:  fmt.Sprintf("public dependency %s is only used by private files of %s", dep, module.Name),
+				})
+			}
+		}
+
+		for _, dep := range dependencies {
+			if _, ok := p.Modules[dep]; !ok {
+				continue
+			}
+
+			if _, isUsed := used[dep]; !isUsed {
+				diagnostics = append(diagnostics, &Diagnostic{
+					File:     module.BuildFile,
+					Line:     findQuotedLine(buildFile, dep),
+					Severity: DiagnosticSeverity_Warning,
+					Code:     DiagnosticCode_UnusedDependency,
+					Message:  fmt.Sprintf("dependency %s is not included by any file of %s", dep, module.Name),
+				})
+			}
+		}
+	}
+
+	SortDiagnostics(diagnostics)
+	return diagnostics, nil
+}
+
+// IsPublicFile returns whether |path| is within the directories the module exposes to others.
+func (m *Module) IsPublicFile(path string) bool {
+	for _, dir := range []string{m.PublicDir(), m.ClassesDir()} {
+		if strings.HasPrefix(path, dir+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// fileModules maps every indexed file to the module that owns it. Engine headers are included if the
+// engine is indexed.
+func (p *Project) fileModules() map[string]*Module {
+	result := map[string]*Module{}
+	for _, module := range p.Modules {
+		for _, file := range module.Files {
+			assignFileOwner(result, file, module)
+		}
+	}
+
+	if p.Engine != nil {
+		for _, module := range p.Engine.Modules {
+			for _, file := range module.Files {
+				if hasAnySuffix(strings.ToLower(file), gIncludeHeaderExtensions) {
+					assignFileOwner(result, file, module)
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+// assignFileOwner records |module| as the owner of |file|, unless a module nested deeper already owns
+// it. Modules are visited in map order, so the longest base directory has to win for the result to be
+// deterministic (the same rule |identifyModule| follows).
+func assignFileOwner(owners map[string]*Module, file string, module *Module) {
+	if existing, ok := owners[file]; ok && len(existing.BaseDir) >= len(module.BaseDir) {
+		return
+	}
+	owners[file] = module
+}
+
+// findQuotedLine returns the first line in |data| that has |value| between double quotes.
+// Returns 0 if not found.
+func findQuotedLine(data []byte, value string) int {
+	index := strings.Index(string(data), fmt.Sprintf("%q", value))
+	if index < 0 {
+		return 0
+	}
+
+	return newLineIndex(data).Line(index)
+}
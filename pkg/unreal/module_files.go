@@ -0,0 +1,136 @@
+package unreal
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	kModuleFileScannerWorkerCount = 100
+)
+
+// ModuleFile is a file that has been attributed to a module.
+type ModuleFile struct {
+	Module *Module
+	Path   string
+}
+
+// moduleFiles returns all the indexed files of the project that pass |filter|.
+// A nil |filter| returns all the files.
+func (p *Project) moduleFiles(filter func(path string) bool) []*ModuleFile {
+	var result []*ModuleFile
+	for _, module := range p.Modules {
+		for _, file := range module.Files {
+			if filter != nil && !filter(file) {
+				continue
+			}
+
+			result = append(result, &ModuleFile{
+				Module: module,
+				Path:   file,
+			})
+		}
+	}
+
+	return result
+}
+
+// sortedModules returns the indexed modules sorted by name.
+func (p *Project) sortedModules() []*Module {
+	modules := make([]*Module, 0, len(p.Modules))
+	for _, module := range p.Modules {
+		modules = append(modules, module)
+	}
+	sort.Slice(modules, func(i, j int) bool {
+		return modules[i].Name < modules[j].Name
+	})
+
+	return modules
+}
+
+// scanModuleFiles runs |scanner| over all the |files| in parallel and collects all the results.
+// The order of the results is not deterministic, so callers are expected to sort them.
+func scanModuleFiles[T any](ctx context.Context, files []*ModuleFile, scanner func(mf *ModuleFile) ([]T, error)) ([]T, error) {
+	g, ctx := errgroup.WithContext(ctx)
+
+	// Produce: all the files to scan.
+	filesCh := make(chan *ModuleFile)
+	{
+		g.Go(func() error {
+			defer close(filesCh)
+
+			for _, file := range files {
+				select {
+				case filesCh <- file:
+					continue
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+
+			return nil
+		})
+	}
+
+	// Map: scan each file.
+	resultsCh := make(chan []T)
+	{
+		var wg sync.WaitGroup
+
+		for i := 0; i < kModuleFileScannerWorkerCount; i++ {
+			wg.Add(1)
+			g.Go(func() error {
+				defer wg.Done()
+
+				for mf := range filesCh {
+					results, err := scanner(mf)
+					if err != nil {
+						return fmt.Errorf("scanning %q: %w", mf.Path, err)
+					}
+
+					if len(results) == 0 {
+						continue
+					}
+
+					select {
+					case resultsCh <- results:
+						continue
+					case <-ctx.Done():
+						return ctx.Err()
+					}
+				}
+
+				return nil
+			})
+		}
+
+		// Make sure the channel will be closed.
+		g.Go(func() error {
+			wg.Wait()
+			close(resultsCh)
+			return nil
+		})
+	}
+
+	// Reduce: collect all the results.
+	var collected []T
+	{
+		g.Go(func() error {
+			for results := range resultsCh {
+				collected = append(collected, results...)
+			}
+
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	return collected, nil
+}
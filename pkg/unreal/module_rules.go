@@ -0,0 +1,107 @@
+package unreal
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+)
+
+// ModuleRules holds the (hackily) parsed information of a module .Build.cs file.
+type ModuleRules struct {
+	PublicDependencies  []string
+	PrivateDependencies []string
+	// PublicIncludePathModules are modules whose public headers are available without linking.
+	PublicIncludePathModules  []string
+	PrivateIncludePathModules []string
+	DynamicallyLoaded         []string
+}
+
+var (
+	gModuleRulesListRegex = regexp.MustCompile(`\b(PublicDependencyModuleNames|PrivateDependencyModuleNames|PublicIncludePathModuleNames|PrivateIncludePathModuleNames|DynamicallyLoadedModuleNames)\s*\.\s*(?:AddRange|Add)\s*\(`)
+	gCSharpStringRegex    = regexp.MustCompile(`"((?:[^"\\]|\\.)*)"`)
+)
+
+// parseModuleRules reads the dependency lists out of the build file at |path|.
+// It does not evaluate any C# logic: every Add/AddRange is taken into account, regardless of the
+// conditions around it.
+func parseModuleRules(path string) (*ModuleRules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %q: %w", path, err)
+	}
+
+	// C# comments are the same as the C++ ones.
+	text := string(stripCppComments(data))
+
+	rules := &ModuleRules{}
+	for _, match := range gModuleRulesListRegex.FindAllStringSubmatchIndex(text, -1) {
+		args, _, ok := extractParens(text, match[1]-1)
+		if !ok {
+			continue
+		}
+
+		var names []string
+		for _, str := range gCSharpStringRegex.FindAllStringSubmatch(args, -1) {
+			names = append(names, str[1])
+		}
+
+		switch text[match[2]:match[3]] {
+		case "PublicDependencyModuleNames":
+			rules.PublicDependencies = append(rules.PublicDependencies, names...)
+		case "PrivateDependencyModuleNames":
+			rules.PrivateDependencies = append(rules.PrivateDependencies, names...)
+		case "PublicIncludePathModuleNames":
+			rules.PublicIncludePathModules = append(rules.PublicIncludePathModules, names...)
+		case "PrivateIncludePathModuleNames":
+			rules.PrivateIncludePathModules = append(rules.PrivateIncludePathModules, names...)
+		case "DynamicallyLoadedModuleNames":
+			rules.DynamicallyLoaded = append(rules.DynamicallyLoaded, names...)
+		}
+	}
+
+	rules.PublicDependencies = sortedUnique(rules.PublicDependencies)
+	rules.PrivateDependencies = sortedUnique(rules.PrivateDependencies)
+	rules.PublicIncludePathModules = sortedUnique(rules.PublicIncludePathModules)
+	rules.PrivateIncludePathModules = sortedUnique(rules.PrivateIncludePathModules)
+	rules.DynamicallyLoaded = sortedUnique(rules.DynamicallyLoaded)
+
+	return rules, nil
+}
+
+// AllDependencies returns every module this module can include headers from, sorted.
+func (mr *ModuleRules) AllDependencies() []string {
+	var all []string
+	all = append(all, mr.PublicDependencies...)
+	all = append(all, mr.PrivateDependencies...)
+	all = append(all, mr.PublicIncludePathModules...)
+	all = append(all, mr.PrivateIncludePathModules...)
+	return sortedUnique(all)
+}
+
+// PublicDependencyNames returns the modules whose headers this module exposes to its dependents.
+func (mr *ModuleRules) PublicDependencyNames() []string {
+	var all []string
+	all = append(all, mr.PublicDependencies...)
+	all = append(all, mr.PublicIncludePathModules...)
+	return sortedUnique(all)
+}
+
+func sortedUnique(list []string) []string {
+	if len(list) == 0 {
+		return nil
+	}
+
+	set := make(map[string]struct{}, len(list))
+	result := make([]string, 0, len(list))
+	for _, value := range list {
+		if _, ok := set[value]; ok {
+			continue
+		}
+		set[value] = struct{}{}
+		result = append(result, value)
+	}
+	sort.Strings(result)
+
+	return result
+}
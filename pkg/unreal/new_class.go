@@ -0,0 +1,344 @@
+package unreal
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/cristiandonosoc/golib/pkg/files"
+)
+
+// gEngineClassIncludes are the headers of the engine classes new classes commonly derive from.
+var gEngineClassIncludes = map[string]string{
+	"UObject":                     "UObject/Object.h",
+	"AActor":                      "GameFramework/Actor.h",
+	"APawn":                       "GameFramework/Pawn.h",
+	"ACharacter":                  "GameFramework/Character.h",
+	"APlayerController":           "GameFramework/PlayerController.h",
+	"AGameModeBase":               "GameFramework/GameModeBase.h",
+	"AGameStateBase":              "GameFramework/GameStateBase.h",
+	"APlayerState":                "GameFramework/PlayerState.h",
+	"AHUD":                        "GameFramework/HUD.h",
+	"UActorComponent":             "Components/ActorComponent.h",
+	"USceneComponent":             "Components/SceneComponent.h",
+	"UPrimitiveComponent":         "Components/PrimitiveComponent.h",
+	"UGameInstance":               "Engine/GameInstance.h",
+	"UDataAsset":                  "Engine/DataAsset.h",
+	"UPrimaryDataAsset":           "Engine/DataAsset.h",
+	"UDeveloperSettings":          "Engine/DeveloperSettings.h",
+	"UBlueprintFunctionLibrary":   "Kismet/BlueprintFunctionLibrary.h",
+	"UGameInstanceSubsystem":      "Subsystems/GameInstanceSubsystem.h",
+	"UWorldSubsystem":             "Subsystems/WorldSubsystem.h",
+	"ULocalPlayerSubsystem":       "Subsystems/LocalPlayerSubsystem.h",
+	"UEngineSubsystem":            "Subsystems/EngineSubsystem.h",
+	"UEditorSubsystem":            "EditorSubsystem.h",
+	"UCheatManager":               "GameFramework/CheatManager.h",
+	"USaveGame":                   "GameFramework/SaveGame.h",
+	"UAnimInstance":               "Animation/AnimInstance.h",
+	"UUserWidget":                 "Blueprint/UserWidget.h",
+	"UInterface":                  "UObject/Interface.h",
+	"UGameViewportClient":         "Engine/GameViewportClient.h",
+	"UCommandlet":                 "Commandlets/Commandlet.h",
+	"UMovementComponent":          "GameFramework/MovementComponent.h",
+	"UCharacterMovementComponent": "GameFramework/CharacterMovementComponent.h",
+}
+
+// NewClassOptions describes the class |NewClass| creates.
+type NewClassOptions struct {
+	Module string
+	// Name of the class, without the Unreal prefix (A, U), which comes from the parent. It is taken as
+	// given, as names like AIDirector are ambiguous.
+	Name   string
+	Parent string
+	// ParentInclude overrides the header to include for |Parent|.
+	ParentInclude string
+	// Private puts the header in Private/ instead of Public/.
+	Private bool
+	// Subdir is an optional directory within Public/ and Private/ for the files.
+	Subdir string
+}
+
+// NewClassTemplateData is what class templates (including the custom ones from the config) get
+// to render.
+type NewClassTemplateData struct {
+	Copyright string
+	Module    string
+	// APIMacro is empty for private classes, as they are not exported.
+	APIMacro string
+	// ClassName has the Unreal prefix (eg. AMyActor), while Name does not (eg. MyActor).
+	ClassName string
+	Name      string
+	Parent    string
+	// ParentInclude is the header that declares |Parent|.
+	ParentInclude string
+	// HeaderInclude is how the .cpp includes the new header.
+	HeaderInclude string
+	IsActor       bool
+	IsComponent   bool
+	// IsInterface is set for UInterface parents. The U-class is then only the reflection side of the
+	// interface, and InterfaceName (eg. IMyInterface) is the class with the actual methods.
+	IsInterface   bool
+	InterfaceName string
+	// ParentInterface is the I-class of |Parent| when it is another interface (eg. IParentInterface).
+	ParentInterface string
+}
+
+const kClassHeaderTemplate = `// {{.Copyright}}
+
+#pragma once
+
+#include "CoreMinimal.h"
+#include "{{.ParentInclude}}"
+
+#include "{{.Name}}.generated.h"
+
+UCLASS({{if .IsComponent}}ClassGroup=(Custom), meta=(BlueprintSpawnableComponent){{end}})
+class {{if .APIMacro}}{{.APIMacro}} {{end}}{{.ClassName}} : public {{.Parent}}
+{
+	GENERATED_BODY()
+{{- if .IsActor}}
+
+public:
+	{{.ClassName}}();
+
+	virtual void Tick(float DeltaTime) override;
+
+protected:
+	virtual void BeginPlay() override;
+{{- else if .IsComponent}}
+
+public:
+	{{.ClassName}}();
+
+	virtual void TickComponent(float DeltaTime, ELevelTick TickType, FActorComponentTickFunction* ThisTickFunction) override;
+
+protected:
+	virtual void BeginPlay() override;
+{{- end}}
+};
+`
+
+const kClassSourceTemplate = `// {{.Copyright}}
+
+#include "{{.HeaderInclude}}"
+{{- if .IsActor}}
+
+{{.ClassName}}::{{.ClassName}}()
+{
+	PrimaryActorTick.bCanEverTick = true;
+}
+
+void {{.ClassName}}::BeginPlay()
+{
+	Super::BeginPlay();
+}
+
+void {{.ClassName}}::Tick(float DeltaTime)
+{
+	Super::Tick(DeltaTime);
+}
+{{- else if .IsComponent}}
+
+{{.ClassName}}::{{.ClassName}}()
+{
+	PrimaryComponentTick.bCanEverTick = true;
+}
+
+void {{.ClassName}}::BeginPlay()
+{
+	Super::BeginPlay();
+}
+
+void {{.ClassName}}::TickComponent(float DeltaTime, ELevelTick TickType, FActorComponentTickFunction* ThisTickFunction)
+{
+	Super::TickComponent(DeltaTime, TickType, ThisTickFunction);
+}
+{{- end}}
+`
+
+const kInterfaceHeaderTemplate = `// {{.Copyright}}
+
+#pragma once
+
+#include "CoreMinimal.h"
+#include "{{.ParentInclude}}"
+
+#include "{{.Name}}.generated.h"
+
+UINTERFACE(MinimalAPI)
+class {{.ClassName}} : public {{.Parent}}
+{
+	GENERATED_BODY()
+};
+
+class {{if .APIMacro}}{{.APIMacro}} {{end}}{{.InterfaceName}}{{if .ParentInterface}} : public {{.ParentInterface}}{{end}}
+{
+	GENERATED_BODY()
+
+public:
+};
+`
+
+const kInterfaceSourceTemplate = `// {{.Copyright}}
+
+#include "{{.HeaderInclude}}"
+`
+
+// NewClass creates the header and source files of a new UCLASS within an indexed module. The
+// templates can be overridden per parent class through the scaffolding config. Returns the paths
+// of the created files.
+func (p *Project) NewClass(ctx context.Context, options *NewClassOptions) ([]string, error) {
+	if !p.IsIndexed() {
+		if err := p.IndexModules(ctx); err != nil {
+			return nil, fmt.Errorf("indexing modules: %w", err)
+		}
+	}
+
+	module, ok := p.Modules[options.Module]
+	if !ok {
+		return nil, fmt.Errorf("module %q not found", options.Module)
+	}
+
+	data, err := p.newClassTemplateData(ctx, module, options)
+	if err != nil {
+		return nil, err
+	}
+
+	headerDir := module.PublicDir()
+	if options.Private {
+		headerDir = module.PrivateDir()
+	}
+	headerPath := filepath.Join(headerDir, filepath.FromSlash(options.Subdir), data.Name+".h")
+	sourcePath := filepath.Join(module.PrivateDir(), filepath.FromSlash(options.Subdir), data.Name+".cpp")
+
+	headerTemplate, sourceTemplate := kClassHeaderTemplate, kClassSourceTemplate
+	if data.IsInterface {
+		headerTemplate, sourceTemplate = kInterfaceHeaderTemplate, kInterfaceSourceTemplate
+	}
+	if custom := p.Config.ScaffoldingConfig.FindClassTemplate(data.Parent); custom != nil {
+		header, err := os.ReadFile(custom.Header)
+		if err != nil {
+			return nil, fmt.Errorf("reading class template: %w", err)
+		}
+		source, err := os.ReadFile(custom.Source)
+		if err != nil {
+			return nil, fmt.Errorf("reading class template: %w", err)
+		}
+		headerTemplate, sourceTemplate = string(header), string(source)
+	}
+
+	rendered, err := renderScaffold([]*scaffoldFile{
+		{Path: headerPath, Template: headerTemplate},
+		{Path: sourcePath, Template: sourceTemplate},
+	}, data)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := writeScaffold(rendered); err != nil {
+		return nil, fmt.Errorf("writing class files: %w", err)
+	}
+
+	return []string{headerPath, sourcePath}, nil
+}
+
+func (p *Project) newClassTemplateData(ctx context.Context, module *Module, options *NewClassOptions) (*NewClassTemplateData, error) {
+	parent := options.Parent
+	if parent == "" {
+		parent = "UObject"
+	}
+	if err := validateScaffoldName("parent class", parent); err != nil {
+		return nil, err
+	}
+	if err := validateScaffoldName("class", options.Name); err != nil {
+		return nil, err
+	}
+	if err := validateScaffoldSubdir(options.Subdir); err != nil {
+		return nil, err
+	}
+
+	prefix := unrealClassPrefix(parent)
+	if prefix == "" {
+		return nil, fmt.Errorf("parent %q does not have an Unreal class prefix (A or U)", parent)
+	}
+
+	name := options.Name
+	data := &NewClassTemplateData{
+		Copyright:     p.Config.ScaffoldingConfig.CopyrightNotice(),
+		Module:        module.Name,
+		ClassName:     prefix + name,
+		Name:          name,
+		Parent:        parent,
+		ParentInclude: options.ParentInclude,
+		HeaderInclude: files.ToUnixPath(filepath.Join(options.Subdir, name+".h")),
+		IsActor:       prefix == "A",
+		IsComponent:   prefix == "U" && strings.HasSuffix(parent, "Component"),
+		IsInterface:   parent == "UInterface",
+		InterfaceName: "I" + name,
+	}
+	if !options.Private {
+		data.APIMacro = strings.ToUpper(module.Name) + "_API"
+	}
+
+	if engineInclude, ok := gEngineClassIncludes[parent]; ok {
+		if data.ParentInclude == "" {
+			data.ParentInclude = engineInclude
+		}
+		return data, nil
+	}
+
+	// Not an engine class we know about, so it should be one of the project.
+	if p.reflectedTypes == nil {
+		if err := p.IndexReflectedTypes(ctx); err != nil {
+			return nil, fmt.Errorf("indexing reflected types: %w", err)
+		}
+	}
+
+	rt, ok := p.FindReflectedType(parent)
+	if !ok {
+		if data.ParentInclude != "" {
+			return data, nil
+		}
+		return nil, fmt.Errorf("parent %q not found. Pass the header that declares it explicitly", parent)
+	}
+
+	data.IsComponent = data.IsComponent || p.DerivesFrom(rt, "UActorComponent")
+	if p.DerivesFrom(rt, "UInterface") {
+		data.IsInterface = true
+		data.ParentInterface = "I" + parent[1:]
+	}
+	if data.ParentInclude == "" {
+		data.ParentInclude = publicIncludePath(&File{Path: rt.File, Module: rt.Module})
+	}
+
+	return data, nil
+}
+
+// unrealClassPrefix returns the Unreal prefix of |name| (eg. "A" for AActor), or empty if it has none.
+func unrealClassPrefix(name string) string {
+	if len(name) < 2 || !unicode.IsUpper(rune(name[1])) {
+		return ""
+	}
+
+	switch name[0] {
+	case 'A', 'U':
+		return name[:1]
+	default:
+		return ""
+	}
+}
+
+// publicIncludePath returns how other files would include |file|: relative to the Public, Private
+// or Classes directory of its module.
+func publicIncludePath(file *File) string {
+	modulePath := file.ModulePath()
+	for _, prefix := range []string{kLayoutPublicPrefix, kLayoutPrivatePrefix, "Classes/"} {
+		if strings.HasPrefix(modulePath, prefix) {
+			return strings.TrimPrefix(modulePath, prefix)
+		}
+	}
+	return modulePath
+}
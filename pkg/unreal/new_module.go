@@ -0,0 +1,277 @@
+package unreal
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cristiandonosoc/golib/pkg/files"
+)
+
+// gModuleHostTypes are the module types a descriptor accepts (UBT's ModuleHostType).
+var gModuleHostTypes = []string{
+	"Runtime",
+	"RuntimeNoCommandlet",
+	"RuntimeAndProgram",
+	"CookedOnly",
+	"UncookedOnly",
+	"Developer",
+	"DeveloperTool",
+	"Editor",
+	"EditorNoCommandlet",
+	"EditorAndProgram",
+	"Program",
+	"ServerOnly",
+	"ClientOnly",
+	"ClientOnlyNoCommandlet",
+}
+
+// gModuleLoadingPhases are the loading phases a descriptor accepts (ELoadingPhase).
+var gModuleLoadingPhases = []string{
+	"EarliestPossible",
+	"PostConfigInit",
+	"PostSplashScreen",
+	"PreEarlyLoadingScreen",
+	"PreLoadingScreen",
+	"PreDefault",
+	"Default",
+	"PostDefault",
+	"PostEngineInit",
+	"None",
+}
+
+// NewModuleHostType normalizes a module type given by the user (eg. "editor" -> "Editor").
+func NewModuleHostType(id string) (string, error) {
+	for _, hostType := range gModuleHostTypes {
+		if strings.EqualFold(id, hostType) {
+			return hostType, nil
+		}
+	}
+	return "", fmt.Errorf("unrecognized module type %q (expected one of %s)", id, strings.Join(gModuleHostTypes, ", "))
+}
+
+// NewModuleLoadingPhase normalizes a loading phase given by the user.
+func NewModuleLoadingPhase(id string) (string, error) {
+	for _, phase := range gModuleLoadingPhases {
+		if strings.EqualFold(id, phase) {
+			return phase, nil
+		}
+	}
+	return "", fmt.Errorf("unrecognized loading phase %q (expected one of %s)", id, strings.Join(gModuleLoadingPhases, ", "))
+}
+
+// NewModuleOptions describes the module |NewModule| creates.
+type NewModuleOptions struct {
+	Name string
+	// Plugin is the plugin that will hold the module. Empty means a project module.
+	Plugin       string
+	Type         string
+	LoadingPhase string
+}
+
+// newModuleTemplateData is what the module templates get to render.
+type newModuleTemplateData struct {
+	Copyright string
+	Name      string
+	APIMacro  string
+	ClassName string
+	IsEditor  bool
+	// Dependencies are extra private dependencies of the module.
+	Dependencies []string
+}
+
+func (p *Project) newModuleTemplateData(name, moduleType string, dependencies []string) *newModuleTemplateData {
+	return &newModuleTemplateData{
+		Copyright: p.Config.ScaffoldingConfig.CopyrightNotice(),
+		Name:      name,
+		APIMacro:  strings.ToUpper(name) + "_API",
+		ClassName: "F" + name + "Module",
+		// Modules that don't make it into game targets are editor (or developer) ones.
+		IsEditor:     !TargetType_Game.AllowsModuleType(moduleType),
+		Dependencies: dependencies,
+	}
+}
+
+// moduleScaffold returns the files of a new module living at |baseDir|.
+func moduleScaffold(baseDir string, data *newModuleTemplateData) []*scaffoldFile {
+	return []*scaffoldFile{
+		{Path: filepath.Join(baseDir, data.Name+".Build.cs"), Template: kModuleBuildFileTemplate},
+		{Path: filepath.Join(baseDir, "Public", data.Name+"Module.h"), Template: kModuleHeaderTemplate},
+		{Path: filepath.Join(baseDir, "Private", data.Name+"Module.cpp"), Template: kModuleSourceTemplate},
+	}
+}
+
+const kModuleBuildFileTemplate = `// {{.Copyright}}
+
+using UnrealBuildTool;
+
+public class {{.Name}} : ModuleRules
+{
+	public {{.Name}}(ReadOnlyTargetRules Target) : base(Target)
+	{
+		PCHUsage = PCHUsageMode.UseExplicitOrSharedPCHs;
+
+		PublicDependencyModuleNames.AddRange(new string[] { "Core" });
+
+		PrivateDependencyModuleNames.AddRange(new string[] {
+			"CoreUObject",
+			"Engine",
+{{- if .IsEditor}}
+			"Slate",
+			"SlateCore",
+			"UnrealEd",
+{{- end}}
+{{- range .Dependencies}}
+			"{{.}}",
+{{- end}}
+		});
+	}
+}
+`
+
+const kModuleHeaderTemplate = `// {{.Copyright}}
+
+#pragma once
+
+#include "CoreMinimal.h"
+#include "Modules/ModuleInterface.h"
+
+class {{.APIMacro}} {{.ClassName}} : public IModuleInterface
+{
+public:
+	virtual void StartupModule() override;
+	virtual void ShutdownModule() override;
+};
+`
+
+const kModuleSourceTemplate = `// {{.Copyright}}
+
+#include "{{.Name}}Module.h"
+
+#include "Modules/ModuleManager.h"
+
+IMPLEMENT_MODULE({{.ClassName}}, {{.Name}})
+
+void {{.ClassName}}::StartupModule()
+{
+}
+
+void {{.ClassName}}::ShutdownModule()
+{
+}
+`
+
+// NewModule creates a new module with the standard Unreal layout (Public/, Private/, the build file
+// and the module interface implementation) and registers it in the .uproject, or the .uplugin if
+// |options.Plugin| is set. The project is re-indexed afterwards to validate the new module.
+func (p *Project) NewModule(ctx context.Context, options *NewModuleOptions) (*Module, error) {
+	if err := validateScaffoldName("module", options.Name); err != nil {
+		return nil, err
+	}
+
+	moduleType, err := NewModuleHostType(options.Type)
+	if err != nil {
+		return nil, err
+	}
+	loadingPhase, err := NewModuleLoadingPhase(options.LoadingPhase)
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.IsIndexed() {
+		if err := p.IndexModules(ctx); err != nil {
+			return nil, fmt.Errorf("indexing modules: %w", err)
+		}
+	}
+
+	if existing, ok := p.Modules[options.Name]; ok {
+		return nil, fmt.Errorf("module %q already exists at %q", options.Name, existing.BaseDir)
+	}
+
+	sourceDir := p.SourceDir()
+	descriptorPath := p.Config.UProjectPath
+	if options.Plugin != "" {
+		plugin, ok := p.Plugins[options.Plugin]
+		if !ok {
+			return nil, fmt.Errorf("plugin %q not found", options.Plugin)
+		}
+		sourceDir = plugin.SourceDir()
+		descriptorPath = plugin.DescriptorPath
+	}
+	if descriptorPath == "" {
+		return nil, fmt.Errorf("no uproject configured to register module %q in", options.Name)
+	}
+
+	descriptor, err := loadDescriptorDocument(descriptorPath)
+	if err != nil {
+		return nil, fmt.Errorf("loading descriptor: %w", err)
+	}
+	if err := descriptor.AddModule(&UProjectModule{
+		Name:         options.Name,
+		Type:         moduleType,
+		LoadingPhase: loadingPhase,
+	}); err != nil {
+		return nil, err
+	}
+
+	baseDir := filepath.Join(sourceDir, options.Name)
+	if exists, err := files.DirExists(baseDir); err != nil {
+		return nil, fmt.Errorf("querying %q: %w", baseDir, err)
+	} else if exists {
+		return nil, fmt.Errorf("module directory %q already exists", baseDir)
+	}
+
+	data := p.newModuleTemplateData(options.Name, moduleType, nil)
+	rendered, err := renderScaffold(moduleScaffold(baseDir, data), data)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := writeScaffold(rendered); err != nil {
+		os.RemoveAll(baseDir)
+		return nil, fmt.Errorf("writing module files: %w", err)
+	}
+
+	if err := descriptor.Save(); err != nil {
+		os.RemoveAll(baseDir)
+		return nil, fmt.Errorf("registering module: %w", err)
+	}
+
+	if err := p.reindex(ctx); err != nil {
+		return nil, err
+	}
+
+	return p.verifyNewModule(options.Name, baseDir)
+}
+
+// reindex reloads the .uproject and indexes the modules again, after the project has been changed.
+func (p *Project) reindex(ctx context.Context) error {
+	if p.Config.UProjectPath != "" {
+		uproject, err := loadUProjectFile(p.Config.UProjectPath)
+		if err != nil {
+			return fmt.Errorf("reloading uproject file: %w", err)
+		}
+		p.LoadedUProject = uproject
+	}
+
+	if err := p.IndexModules(ctx); err != nil {
+		return fmt.Errorf("re-indexing modules: %w", err)
+	}
+
+	return nil
+}
+
+// verifyNewModule checks that the re-indexed project found module |name| where it was created.
+func (p *Project) verifyNewModule(name, baseDir string) (*Module, error) {
+	module, ok := p.Modules[name]
+	if !ok {
+		return nil, fmt.Errorf("module %q was created but indexing did not find it", name)
+	}
+	if module.BaseDir != baseDir {
+		return nil, fmt.Errorf("module %q was indexed at %q instead of %q", name, module.BaseDir, baseDir)
+	}
+
+	return module, nil
+}
@@ -0,0 +1,207 @@
+package unreal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cristiandonosoc/golib/pkg/files"
+)
+
+// PluginTemplate is the shape of the plugin |NewPlugin| creates.
+type PluginTemplate string
+
+const (
+	// PluginTemplate_Blank has a single runtime module.
+	PluginTemplate_Blank PluginTemplate = "blank"
+	// PluginTemplate_EditorOnly has a single editor module.
+	PluginTemplate_EditorOnly PluginTemplate = "editor-only"
+	// PluginTemplate_RuntimeEditor has a runtime module plus an editor module that depends on it.
+	PluginTemplate_RuntimeEditor PluginTemplate = "runtime-editor"
+)
+
+func NewPluginTemplate(id string) (PluginTemplate, error) {
+	switch strings.ToLower(id) {
+	case "blank", "":
+		return PluginTemplate_Blank, nil
+	case "editor-only", "editor":
+		return PluginTemplate_EditorOnly, nil
+	case "runtime-editor", "runtime+editor":
+		return PluginTemplate_RuntimeEditor, nil
+	default:
+		return "", fmt.Errorf("unrecognized plugin template %q", id)
+	}
+}
+
+// NewPluginOptions describes the plugin |NewPlugin| creates.
+type NewPluginOptions struct {
+	Name        string
+	Template    PluginTemplate
+	Description string
+	Category    string
+	CreatedBy   string
+	// Content creates the Content/ directory and marks the plugin as able to hold content.
+	Content bool
+	// IconPath is an optional PNG to copy as the plugin icon (Resources/Icon128.png).
+	IconPath string
+}
+
+// pluginModuleSpec is a module a plugin template generates.
+type pluginModuleSpec struct {
+	descriptor   *UProjectModule
+	dependencies []string
+}
+
+func (pt PluginTemplate) modules(name string) []*pluginModuleSpec {
+	switch pt {
+	case PluginTemplate_EditorOnly:
+		return []*pluginModuleSpec{
+			{descriptor: &UProjectModule{Name: name, Type: "Editor", LoadingPhase: "PostEngineInit"}},
+		}
+	case PluginTemplate_RuntimeEditor:
+		return []*pluginModuleSpec{
+			{descriptor: &UProjectModule{Name: name, Type: "Runtime", LoadingPhase: "Default"}},
+			{
+				descriptor:   &UProjectModule{Name: name + "Editor", Type: "Editor", LoadingPhase: "PostEngineInit"},
+				dependencies: []string{name},
+			},
+		}
+	default:
+		return []*pluginModuleSpec{
+			{descriptor: &UProjectModule{Name: name, Type: "Runtime", LoadingPhase: "Default"}},
+		}
+	}
+}
+
+// NewPlugin creates a new plugin within the project Plugins directory: its .uplugin descriptor and
+// the modules of |options.Template|. The plugin gets enabled in the .uproject and the project is
+// re-indexed afterwards to validate it.
+func (p *Project) NewPlugin(ctx context.Context, options *NewPluginOptions) (*Plugin, error) {
+	if err := validateScaffoldName("plugin", options.Name); err != nil {
+		return nil, err
+	}
+
+	if p.Config.UProjectPath == "" {
+		return nil, fmt.Errorf("no uproject configured to enable plugin %q in", options.Name)
+	}
+
+	if !p.IsIndexed() {
+		if err := p.IndexModules(ctx); err != nil {
+			return nil, fmt.Errorf("indexing modules: %w", err)
+		}
+	}
+
+	if existing, ok := p.Plugins[options.Name]; ok {
+		return nil, fmt.Errorf("plugin %q already exists at %q", options.Name, existing.BaseDir)
+	}
+
+	baseDir := filepath.Join(p.PluginsDir(), options.Name)
+	if exists, err := files.DirExists(baseDir); err != nil {
+		return nil, fmt.Errorf("querying %q: %w", baseDir, err)
+	} else if exists {
+		return nil, fmt.Errorf("plugin directory %q already exists", baseDir)
+	}
+
+	category := options.Category
+	if category == "" {
+		category = "Other"
+	}
+
+	descriptor := &UPlugin{
+		FileVersion:       3,
+		Version:           1,
+		VersionName:       "1.0",
+		FriendlyName:      options.Name,
+		Description:       options.Description,
+		Category:          category,
+		CreatedBy:         options.CreatedBy,
+		CanContainContent: options.Content,
+	}
+
+	// Render everything first, so that we don't leave a half created plugin behind.
+	rendered := map[string][]byte{}
+	for _, spec := range options.Template.modules(options.Name) {
+		if existing, ok := p.Modules[spec.descriptor.Name]; ok {
+			return nil, fmt.Errorf("module %q already exists at %q", spec.descriptor.Name, existing.BaseDir)
+		}
+		descriptor.Modules = append(descriptor.Modules, spec.descriptor)
+
+		moduleDir := filepath.Join(baseDir, "Source", spec.descriptor.Name)
+		data := p.newModuleTemplateData(spec.descriptor.Name, spec.descriptor.Type, spec.dependencies)
+		moduleFiles, err := renderScaffold(moduleScaffold(moduleDir, data), data)
+		if err != nil {
+			return nil, err
+		}
+		for path, content := range moduleFiles {
+			rendered[path] = content
+		}
+	}
+
+	descriptorData, err := json.MarshalIndent(descriptor, "", "\t")
+	if err != nil {
+		return nil, fmt.Errorf("marshalling plugin descriptor: %w", err)
+	}
+	descriptorPath := filepath.Join(baseDir, options.Name+UnrealPluginFileExtension)
+	rendered[descriptorPath] = append(descriptorData, '\n')
+
+	uproject, err := loadDescriptorDocument(p.Config.UProjectPath)
+	if err != nil {
+		return nil, fmt.Errorf("loading uproject: %w", err)
+	}
+	if err := uproject.AddPlugin(&UProjectPlugin{Name: options.Name, Enabled: true}); err != nil {
+		return nil, err
+	}
+
+	if err := p.writePluginScaffold(baseDir, rendered, options); err != nil {
+		os.RemoveAll(baseDir)
+		return nil, err
+	}
+
+	if err := uproject.Save(); err != nil {
+		os.RemoveAll(baseDir)
+		return nil, fmt.Errorf("enabling plugin: %w", err)
+	}
+
+	if err := p.reindex(ctx); err != nil {
+		return nil, err
+	}
+
+	plugin, ok := p.Plugins[options.Name]
+	if !ok {
+		return nil, fmt.Errorf("plugin %q was created but indexing did not find it", options.Name)
+	}
+	for _, module := range descriptor.Modules {
+		if _, err := p.verifyNewModule(module.Name, filepath.Join(plugin.SourceDir(), module.Name)); err != nil {
+			return nil, err
+		}
+	}
+
+	return plugin, nil
+}
+
+func (p *Project) writePluginScaffold(baseDir string, rendered map[string][]byte, options *NewPluginOptions) error {
+	if err := writeScaffold(rendered); err != nil {
+		return fmt.Errorf("writing plugin files: %w", err)
+	}
+
+	if options.Content {
+		contentDir := filepath.Join(baseDir, "Content")
+		if err := os.MkdirAll(contentDir, 0755); err != nil {
+			return fmt.Errorf("creating %q: %w", contentDir, err)
+		}
+	}
+
+	if options.IconPath != "" {
+		iconPath := filepath.Join(baseDir, "Resources", "Icon128.png")
+		copyOptions := files.GDefaultCopyFileAdvancedOptions
+		copyOptions.DstCreateDir = true
+		if err := files.CopyFileAdvanced(options.IconPath, iconPath, &copyOptions); err != nil {
+			return fmt.Errorf("copying icon: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package unreal
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// NonUnityCheckOptions configures |NonUnityCheck|.
+type NonUnityCheckOptions struct {
+	// Since is the git ref to compare against to find the touched modules.
+	Since string
+	// Target is the UBT target to build (eg. MyGameEditor).
+	Target        string
+	Platform      Platform
+	Configuration string
+	// DisablePCH also turns off precompiled headers, which can hide missing includes the same way
+	// unity batching does.
+	DisablePCH bool
+	// DryRun only calculates the modules and the UBT arguments, without running UBT.
+	DryRun bool
+	// Output optionally receives the UBT output as it runs.
+	Output io.Writer
+}
+
+// NonUnityCheckResult is the outcome of |NonUnityCheck|.
+type NonUnityCheckResult struct {
+	// Modules are the modules that were built, sorted by name.
+	Modules []*Module
+	// Args are the arguments UBT was called with.
+	Args        []string
+	Diagnostics []*Diagnostic
+}
+
+// NonUnityCheck builds the modules touched since |options.Since| without unity batching. Unity builds
+// glue many .cpp files together, so a file that forgets an #include can still compile because an
+// earlier file in the batch included it. Building each file on its own surfaces those errors.
+// Only the touched modules are built, as a full non-unity build is very slow.
+func (p *Project) NonUnityCheck(ctx context.Context, options *NonUnityCheckOptions) (*NonUnityCheckResult, error) {
+	if !p.IsIndexed() {
+		if err := p.IndexModules(ctx); err != nil {
+			return nil, fmt.Errorf("indexing modules: %w", err)
+		}
+	}
+
+	changed, err := p.ChangedFilesSince(options.Since)
+	if err != nil {
+		return nil, fmt.Errorf("finding changed files: %w", err)
+	}
+
+	result := &NonUnityCheckResult{
+		Modules: p.ModulesOfFiles(changed),
+	}
+	if len(result.Modules) == 0 {
+		return result, nil
+	}
+
+	result.Args = []string{options.Target, string(options.Platform), options.Configuration, "-DisableUnity"}
+	if options.DisablePCH {
+		result.Args = append(result.Args, "-NoPCH", "-NoSharedPCH")
+	}
+	for _, module := range result.Modules {
+		result.Args = append(result.Args, fmt.Sprintf("-Module=%s", module.Name))
+	}
+
+	if options.DryRun {
+		return result, nil
+	}
+
+	var output bytes.Buffer
+	var ubtOutput io.Writer = &output
+	if options.Output != nil {
+		ubtOutput = io.MultiWriter(&output, options.Output)
+	}
+	ubtErr := p.UBTWithOutput(result.Args, ubtOutput)
+
+	diagnostics, err := ParseCompilerDiagnostics(&output)
+	if err != nil {
+		return nil, fmt.Errorf("parsing UBT output: %w", err)
+	}
+	SortDiagnostics(diagnostics)
+	result.Diagnostics = diagnostics
+
+	// If UBT failed but we could not attribute the failure to any file (eg. a linker or setup error),
+	// the caller would not know something went wrong.
+	if ubtErr != nil && CountDiagnostics(diagnostics, DiagnosticSeverity_Error) == 0 {
+		return nil, fmt.Errorf("running UBT: %w", ubtErr)
+	}
+
+	return result, nil
+}
+
+// ModulesOfFiles returns the modules that own any of |paths|, sorted by name.
+// Paths that do not belong to any indexed module (eg. content or config files) are ignored.
+func (p *Project) ModulesOfFiles(paths []string) []*Module {
+	found := map[string]*Module{}
+	for _, path := range paths {
+		module, err := p.identifyModule(path)
+		if err != nil {
+			continue
+		}
+		found[module.Name] = module
+	}
+
+	modules := make([]*Module, 0, len(found))
+	for _, module := range found {
+		modules = append(modules, module)
+	}
+	sort.Slice(modules, func(i, j int) bool {
+		return modules[i].Name < modules[j].Name
+	})
+
+	return modules
+}
@@ -0,0 +1,249 @@
+package unreal
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cristiandonosoc/gunreal/pkg/config"
+)
+
+var (
+	// gUATStageRegex matches the banners BuildCookRun prints around each stage:
+	// "********** COOK COMMAND STARTED **********".
+	gUATStageRegex = regexp.MustCompile(`\*{5,}\s+(\w+) COMMAND (STARTED|COMPLETED)\s+\*{5,}`)
+
+	// gUATErrorRegex matches the errors UAT and the tools it runs (eg. the cooker) log:
+	// "ERROR: Missing precompiled manifest" or "LogCook: Error: Failed to save package".
+	gUATErrorRegex = regexp.MustCompile(`^\s*(?:.*?:\s+)??(?:(Log\w+):\s+)?(?:ERROR|Error):\s+(.*)$`)
+)
+
+// PackageStageStatus is the state of a BuildCookRun stage.
+type PackageStageStatus string
+
+const (
+	PackageStageStatus_Started   PackageStageStatus = "started"
+	PackageStageStatus_Completed PackageStageStatus = "completed"
+	PackageStageStatus_Failed    PackageStageStatus = "failed"
+)
+
+// PackageStage is a BuildCookRun stage (build, cook, stage, package, archive...).
+type PackageStage struct {
+	Name     string             `json:"name"`
+	Status   PackageStageStatus `json:"status"`
+	Duration time.Duration      `json:"-"`
+	// Seconds is |Duration| for the JSON output.
+	Seconds float64 `json:"seconds"`
+
+	started time.Time
+}
+
+// PackageOptions describes how |Package| runs BuildCookRun.
+type PackageOptions struct {
+	Profile *config.GunrealPackageProfile
+	// Output optionally receives the UAT output as it runs.
+	Output io.Writer
+	// OnStage is optionally called every time a stage starts or finishes.
+	OnStage func(stage *PackageStage)
+}
+
+// PackageResult is the outcome of |Package|.
+type PackageResult struct {
+	// Args are the arguments UAT was called with.
+	Args   []string        `json:"args"`
+	Stages []*PackageStage `json:"stages"`
+	// Diagnostics are the errors found in the output (compiler, cooker and UAT errors).
+	Diagnostics []*Diagnostic `json:"diagnostics"`
+	// Error is set if UAT failed.
+	Error string `json:"error,omitempty"`
+}
+
+// PackageArgs returns the BuildCookRun arguments for |profile|.
+func (p *Project) PackageArgs(profile *config.GunrealPackageProfile) ([]string, error) {
+	if p.Config.UProjectPath == "" {
+		return nil, fmt.Errorf("no uproject configured to package")
+	}
+
+	args := []string{
+		"BuildCookRun",
+		"-project=" + p.Config.UProjectPath,
+		"-noP4",
+		"-utf8output",
+		"-unattended",
+		// Passed as written: UAT knows more platforms (Android, consoles...) than gunreal runs on.
+		"-platform=" + profile.Platform,
+		"-clientconfig=" + profile.Configuration,
+		"-build",
+		"-cook",
+		"-stage",
+		"-package",
+	}
+
+	if profile.Target != "" {
+		args = append(args, "-target="+profile.Target)
+	}
+	if profile.CookFlavor != "" {
+		args = append(args, "-cookflavor="+profile.CookFlavor)
+	}
+	if profile.Pak {
+		args = append(args, "-pak")
+	}
+	if profile.IoStore {
+		args = append(args, "-iostore")
+	}
+	if profile.ArchiveDirectory != "" {
+		args = append(args, "-archive", "-archivedirectory="+profile.ArchiveDirectory)
+	}
+	if len(profile.Maps) > 0 {
+		args = append(args, "-map="+strings.Join(profile.Maps, "+"))
+	}
+	args = append(args, profile.Args...)
+
+	return args, nil
+}
+
+// Package runs BuildCookRun with |options.Profile|, following the stages as they go and collecting
+// the errors from the output. A failed run is reported in |PackageResult.Error| rather than as an
+// error, so that callers still get the stages and diagnostics that explain it.
+func (p *Project) Package(options *PackageOptions) (*PackageResult, error) {
+	args, err := p.PackageArgs(options.Profile)
+	if err != nil {
+		return nil, err
+	}
+
+	parser := newUATOutputParser(options.Output, options.OnStage)
+	uatErr := p.UATWithOutput(args, parser)
+	parser.Flush()
+
+	result := &PackageResult{
+		Args:        args,
+		Stages:      parser.stages,
+		Diagnostics: parser.diagnostics,
+	}
+
+	if uatErr != nil {
+		result.Error = uatErr.Error()
+
+		// Whatever stage was running when UAT died is the one that failed.
+		for _, stage := range result.Stages {
+			if stage.Status == PackageStageStatus_Started {
+				parser.finishStage(stage, PackageStageStatus_Failed)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// uatOutputParser is an io.Writer that follows the UAT output line by line, tracking the stages and
+// the errors while passing the output through.
+type uatOutputParser struct {
+	output  io.Writer
+	onStage func(stage *PackageStage)
+
+	mu          sync.Mutex
+	pending     []byte
+	stages      []*PackageStage
+	diagnostics []*Diagnostic
+	seen        map[string]struct{}
+}
+
+func newUATOutputParser(output io.Writer, onStage func(stage *PackageStage)) *uatOutputParser {
+	return &uatOutputParser{
+		output:  output,
+		onStage: onStage,
+		seen:    map[string]struct{}{},
+	}
+}
+
+func (up *uatOutputParser) Write(data []byte) (int, error) {
+	up.mu.Lock()
+	defer up.mu.Unlock()
+
+	if up.output != nil {
+		if _, err := up.output.Write(data); err != nil {
+			return 0, err
+		}
+	}
+
+	up.pending = append(up.pending, data...)
+	for {
+		index := bytes.IndexByte(up.pending, '\n')
+		if index < 0 {
+			break
+		}
+
+		up.parseLine(strings.TrimRight(string(up.pending[:index]), "\r"))
+		up.pending = up.pending[index+1:]
+	}
+
+	return len(data), nil
+}
+
+// Flush parses the last line, if the output did not end with a newline.
+func (up *uatOutputParser) Flush() {
+	up.mu.Lock()
+	defer up.mu.Unlock()
+
+	if len(up.pending) > 0 {
+		up.parseLine(strings.TrimRight(string(up.pending), "\r"))
+		up.pending = nil
+	}
+}
+
+func (up *uatOutputParser) parseLine(line string) {
+	if match := gUATStageRegex.FindStringSubmatch(line); match != nil {
+		name := strings.ToLower(match[1])
+		if match[2] == "STARTED" {
+			stage := &PackageStage{Name: name, Status: PackageStageStatus_Started, started: time.Now()}
+			up.stages = append(up.stages, stage)
+			if up.onStage != nil {
+				up.onStage(stage)
+			}
+			return
+		}
+
+		for i := len(up.stages) - 1; i >= 0; i-- {
+			if stage := up.stages[i]; stage.Name == name && stage.Status == PackageStageStatus_Started {
+				up.finishStage(stage, PackageStageStatus_Completed)
+				break
+			}
+		}
+		return
+	}
+
+	diagnostic := parseCompilerDiagnostic(line)
+	if diagnostic == nil {
+		if match := gUATErrorRegex.FindStringSubmatch(line); match != nil {
+			diagnostic = &Diagnostic{
+				Severity: DiagnosticSeverity_Error,
+				Code:     match[1],
+				Message:  strings.TrimSpace(match[2]),
+			}
+		}
+	}
+	if diagnostic == nil || diagnostic.Severity != DiagnosticSeverity_Error {
+		return
+	}
+
+	// Errors are usually repeated in the summary at the end.
+	key := diagnostic.String()
+	if _, ok := up.seen[key]; ok {
+		return
+	}
+	up.seen[key] = struct{}{}
+	up.diagnostics = append(up.diagnostics, diagnostic)
+}
+
+func (up *uatOutputParser) finishStage(stage *PackageStage, status PackageStageStatus) {
+	stage.Status = status
+	stage.Duration = time.Since(stage.started)
+	stage.Seconds = stage.Duration.Seconds()
+	if up.onStage != nil {
+		up.onStage(stage)
+	}
+}
@@ -2,6 +2,7 @@ package unreal
 
 import (
 	"fmt"
+	"runtime"
 	"strings"
 )
 
@@ -9,6 +10,8 @@ type Platform string
 
 const (
 	Platform_Windows = "Win64"
+	Platform_Linux   = "Linux"
+	Platform_Mac     = "Mac"
 )
 
 // NewUnrealPlatform attempts to unify the unreal platform from identifiers that might come from the
@@ -17,11 +20,20 @@ func NewUnrealPlatform(id string) (Platform, error) {
 	switch strings.ToLower(id) {
 	case "win64", "windows":
 		return Platform_Windows, nil
+	case "linux":
+		return Platform_Linux, nil
+	case "mac", "macos", "darwin":
+		return Platform_Mac, nil
 	default:
 		return "", fmt.Errorf("unrecognized unreal platform %q", id)
 	}
 }
 
+// HostPlatform returns the unreal platform gunreal is running on.
+func HostPlatform() (Platform, error) {
+	return NewUnrealPlatform(runtime.GOOS)
+}
+
 func (up *Platform) String() string {
 	return string(*up)
 }
@@ -0,0 +1,99 @@
+package unreal
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	UnrealPluginFileExtension = ".uplugin"
+)
+
+// Plugin represents an unreal plugin within the project.
+type Plugin struct {
+	Name string
+	// BaseDir is the directory that holds the .uplugin file.
+	BaseDir        string
+	DescriptorPath string
+	Descriptor     *UPlugin
+}
+
+func (pl *Plugin) String() string {
+	return fmt.Sprintf("%s (%s)", pl.Name, pl.BaseDir)
+}
+
+// SourceDir is where the modules of the plugin live.
+func (pl *Plugin) SourceDir() string {
+	return filepath.Join(pl.BaseDir, "Source")
+}
+
+// findPlugins searches |pluginsDir| for plugin descriptors. A non-existent |pluginsDir| is not an
+// error, as most projects don't have plugins.
+func findPlugins(pluginsDir string) (map[string]*Plugin, error) {
+	descriptors, err := findPluginDescriptors(pluginsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	plugins := map[string]*Plugin{}
+	for _, path := range descriptors {
+		name := pluginNameFromDescriptor(path)
+		if existing, ok := plugins[name]; ok {
+			return nil, fmt.Errorf("plugin %q found more than once (%q and %q)", name, existing.DescriptorPath, path)
+		}
+
+		descriptor, err := loadUPluginFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("loading plugin %q: %w", name, err)
+		}
+
+		plugins[name] = &Plugin{
+			Name:           name,
+			BaseDir:        filepath.Dir(path),
+			DescriptorPath: path,
+			Descriptor:     descriptor,
+		}
+	}
+
+	return plugins, nil
+}
+
+// findPluginDescriptors returns the paths of all the .uplugin files within |pluginsDir|, sorted.
+func findPluginDescriptors(pluginsDir string) ([]string, error) {
+	var descriptors []string
+	err := filepath.WalkDir(pluginsDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return fmt.Errorf("path %q: %w", path, err)
+		}
+
+		if d.IsDir() {
+			// These directories are big and are never going to hold another plugin.
+			switch d.Name() {
+			case "Source", "Content", "Intermediate", "Binaries", "Resources", "Saved":
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if strings.HasSuffix(strings.ToLower(path), UnrealPluginFileExtension) {
+			descriptors = append(descriptors, path)
+		}
+		return nil
+	})
+
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("walking %q: %w", pluginsDir, err)
+	}
+
+	sort.Strings(descriptors)
+	return descriptors, nil
+}
+
+func pluginNameFromDescriptor(path string) string {
+	return strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+}
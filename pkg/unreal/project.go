@@ -28,6 +28,14 @@ type Project struct {
 
 	LoadedUProject *UProject
 	Modules        map[string]*Module
+	Plugins        map[string]*Plugin
+	Targets        map[string]*Target
+
+	// Engine is the (optional) index of the engine modules. See |IndexEngine|.
+	Engine *EngineIndex
+
+	reflectedTypes []*ReflectedType
+	includeGraph   *IncludeGraph
 }
 
 func NewProjectFromPath(projectDir string) (*Project, error) {
@@ -81,7 +89,18 @@ func (p *Project) SourceDir() string {
 	return filepath.Join(p.ProjectDir(), "Source")
 }
 
-// IndexModules goes and collects all the modules within the project.
+// GunrealDir is where gunreal stores its generated artifacts and caches for the project.
+func (p *Project) GunrealDir() string {
+	return filepath.Join(p.ProjectDir(), ".gunreal")
+}
+
+// PluginsDir is where the project plugins live.
+func (p *Project) PluginsDir() string {
+	return filepath.Join(p.ProjectDir(), "Plugins")
+}
+
+// IndexModules goes and collects all the modules within the project, including the ones within
+// the project plugins.
 func (p *Project) IndexModules(ctx context.Context) error {
 	modules, err := collectModules(ctx, p.SourceDir())
 	if err != nil {
@@ -92,11 +111,46 @@ func (p *Project) IndexModules(ctx context.Context) error {
 		return fmt.Errorf("no modules found at %q. Is it an Unreal project?", p.ProjectDir())
 	}
 
+	plugins, err := findPlugins(p.PluginsDir())
+	if err != nil {
+		return fmt.Errorf("finding plugins: %w", err)
+	}
+
+	for _, plugin := range plugins {
+		if exists, err := files.DirExists(plugin.SourceDir()); err != nil {
+			return fmt.Errorf("querying plugin source dir %q: %w", plugin.SourceDir(), err)
+		} else if !exists {
+			// Content only plugin.
+			continue
+		}
+
+		pluginModules, err := collectModules(ctx, plugin.SourceDir())
+		if err != nil {
+			return fmt.Errorf("collecting modules for plugin %q: %w", plugin.Name, err)
+		}
+
+		for name, module := range pluginModules {
+			if existing, ok := modules[name]; ok {
+				return fmt.Errorf("module %q found more than once (%q and %q)", name, existing.BaseDir, module.BaseDir)
+			}
+
+			module.Plugin = plugin
+			modules[name] = module
+		}
+	}
+
+	targets, err := findTargets(p.SourceDir())
+	if err != nil {
+		return fmt.Errorf("finding targets: %w", err)
+	}
+
 	// Make sure all the modules point back to the project.
 	for _, module := range modules {
 		module.project = p
 	}
 	p.Modules = modules
+	p.Plugins = plugins
+	p.Targets = targets
 
 	return nil
 }
@@ -307,9 +361,16 @@ func (p *Project) Describe() (string, error) {
 			sb.WriteString("\n")
 		}
 		sb.WriteString(fmt.Sprintf("- MODULE: %s\n", module.Name))
+		if module.Plugin != nil {
+			sb.WriteString(fmt.Sprintf("  - PLUGIN: %s\n", module.Plugin.Name))
+		}
 		sb.WriteString(fmt.Sprintf("  - BASE DIR: %s\n", module.BaseDir))
 		sb.WriteString(fmt.Sprintf("  - BUILD FILE: %s\n", module.BuildFile))
 		sb.WriteString(fmt.Sprintf("  - FILES: %d\n", len(module.Files)))
+		if module.Rules != nil {
+			sb.WriteString(fmt.Sprintf("  - PUBLIC DEPENDENCIES: %s\n", strings.Join(module.Rules.PublicDependencies, ", ")))
+			sb.WriteString(fmt.Sprintf("  - PRIVATE DEPENDENCIES: %s\n", strings.Join(module.Rules.PrivateDependencies, ", ")))
+		}
 		// for _, file := range module.Files {
 		// 	fmt.Println("-", file)
 		// }
@@ -319,5 +380,11 @@ func (p *Project) Describe() (string, error) {
 		// }
 	}
 
+	sb.WriteString("\nTARGETS ------------------------------------------------------------------\n\n")
+	for _, target := range p.sortedTargets() {
+		sb.WriteString(fmt.Sprintf("- TARGET: %s\n", target))
+		sb.WriteString(fmt.Sprintf("  - MODULES: %s\n", strings.Join(p.TargetModules(target), ", ")))
+	}
+
 	return sb.String(), nil
 }
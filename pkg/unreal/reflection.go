@@ -0,0 +1,419 @@
+package unreal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ReflectedKind is the kind of declaration UHT generates reflection data for.
+type ReflectedKind string
+
+const (
+	ReflectedKind_Class     ReflectedKind = "UCLASS"
+	ReflectedKind_Struct    ReflectedKind = "USTRUCT"
+	ReflectedKind_Enum      ReflectedKind = "UENUM"
+	ReflectedKind_Interface ReflectedKind = "UINTERFACE"
+	ReflectedKind_Delegate  ReflectedKind = "UDELEGATE"
+)
+
+// NewReflectedKind attempts to unify the reflected kind from identifiers that might come from the
+// outside.
+func NewReflectedKind(id string) (ReflectedKind, error) {
+	switch strings.ToLower(id) {
+	case "uclass", "class":
+		return ReflectedKind_Class, nil
+	case "ustruct", "struct":
+		return ReflectedKind_Struct, nil
+	case "uenum", "enum":
+		return ReflectedKind_Enum, nil
+	case "uinterface", "interface":
+		return ReflectedKind_Interface, nil
+	case "udelegate", "delegate":
+		return ReflectedKind_Delegate, nil
+	default:
+		return "", fmt.Errorf("unrecognized reflected kind %q", id)
+	}
+}
+
+// ReflectedMember is a UFUNCTION or UPROPERTY declared within a reflected type.
+type ReflectedMember struct {
+	Name string `json:"name"`
+	// Type is the type of the property or the return type (with qualifiers) of the function.
+	Type       string            `json:"type"`
+	Specifiers []string          `json:"specifiers,omitempty"`
+	Meta       map[string]string `json:"meta,omitempty"`
+	Line       int               `json:"line"`
+}
+
+// ReflectedType is a declaration annotated with one of the UHT type macros.
+type ReflectedType struct {
+	Kind ReflectedKind `json:"kind"`
+	Name string        `json:"name"`
+	// Parent is the first base class, if any.
+	Parent string `json:"parent,omitempty"`
+	// Interfaces are the rest of the base classes.
+	Interfaces []string `json:"interfaces,omitempty"`
+	// APIMacro is the export macro the type was declared with (eg. MYMODULE_API).
+	APIMacro   string            `json:"api_macro,omitempty"`
+	Specifiers []string          `json:"specifiers,omitempty"`
+	Meta       map[string]string `json:"meta,omitempty"`
+
+	Functions  []*ReflectedMember `json:"functions,omitempty"`
+	Properties []*ReflectedMember `json:"properties,omitempty"`
+
+	Module *Module `json:"-"`
+	File   string  `json:"file"`
+	Line   int     `json:"line"`
+}
+
+// MarshalJSON outputs the module by name, as modules point back to the whole project.
+func (rt *ReflectedType) MarshalJSON() ([]byte, error) {
+	type alias ReflectedType
+	var moduleName string
+	if rt.Module != nil {
+		moduleName = rt.Module.Name
+	}
+
+	return json.Marshal(&struct {
+		*alias
+		ModuleName string `json:"module"`
+	}{
+		alias:      (*alias)(rt),
+		ModuleName: moduleName,
+	})
+}
+
+// HasSpecifier returns whether the type was declared with |specifier| (case insensitive).
+// Specifiers with values (eg. "Within=Foo") are matched by their key.
+func (rt *ReflectedType) HasSpecifier(specifier string) bool {
+	return hasSpecifier(rt.Specifiers, specifier)
+}
+
+func (rt *ReflectedType) String() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s %s", rt.Kind, rt.Name))
+	if rt.Parent != "" {
+		sb.WriteString(fmt.Sprintf(" : %s", rt.Parent))
+	}
+	if len(rt.Specifiers) > 0 {
+		sb.WriteString(fmt.Sprintf(" (%s)", strings.Join(rt.Specifiers, ", ")))
+	}
+	return sb.String()
+}
+
+func hasSpecifier(specifiers []string, specifier string) bool {
+	for _, s := range specifiers {
+		key, _, _ := strings.Cut(s, "=")
+		if strings.EqualFold(strings.TrimSpace(key), specifier) {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	gReflectionMacroRegex = regexp.MustCompile(`\b(UCLASS|USTRUCT|UENUM|UINTERFACE|UDELEGATE|UFUNCTION|UPROPERTY)\s*\(`)
+
+	gReflectedClassRegex    = regexp.MustCompile(`^(?:class|struct)\s+(?:(\w+_API)\s+)?(\w+)(?:\s+final)?\s*(?::\s*(.*))?$`)
+	gReflectedEnumRegex     = regexp.MustCompile(`^(?:enum\s+(?:class\s+|struct\s+)?|namespace\s+)(\w+)`)
+	gReflectedDelegateRegex = regexp.MustCompile(`^DECLARE_\w+\s*\(\s*(\w+)`)
+	gWhitespaceRegex        = regexp.MustCompile(`\s+`)
+)
+
+// IndexReflectedTypes scans the headers of all the indexed modules in parallel, searching for the
+// declarations UHT would generate code for.
+func (p *Project) IndexReflectedTypes(ctx context.Context) error {
+	if !p.IsIndexed() {
+		return fmt.Errorf("no modules loaded. Is the project indexed?")
+	}
+
+	headers := p.moduleFiles(func(path string) bool {
+		return strings.HasSuffix(strings.ToLower(path), ".h")
+	})
+
+	types, err := scanModuleFiles(ctx, headers, func(mf *ModuleFile) ([]*ReflectedType, error) {
+		data, err := os.ReadFile(mf.Path)
+		if err != nil {
+			return nil, fmt.Errorf("reading %q: %w", mf.Path, err)
+		}
+
+		types := parseReflectedTypes(data)
+		for _, rt := range types {
+			rt.Module = mf.Module
+			rt.File = mf.Path
+		}
+		return types, nil
+	})
+	if err != nil {
+		return fmt.Errorf("scanning headers: %w", err)
+	}
+
+	sort.Slice(types, func(i, j int) bool {
+		if types[i].Name != types[j].Name {
+			return types[i].Name < types[j].Name
+		}
+		return types[i].File < types[j].File
+	})
+
+	p.reflectedTypes = types
+	return nil
+}
+
+// ReflectedTypes returns all the reflected types found by |IndexReflectedTypes|, sorted by name.
+func (p *Project) ReflectedTypes() []*ReflectedType {
+	return p.reflectedTypes
+}
+
+// FindReflectedType searches an indexed reflected type by name.
+func (p *Project) FindReflectedType(name string) (*ReflectedType, bool) {
+	index := sort.Search(len(p.reflectedTypes), func(i int) bool {
+		return p.reflectedTypes[i].Name >= name
+	})
+
+	if index < len(p.reflectedTypes) && p.reflectedTypes[index].Name == name {
+		return p.reflectedTypes[index], true
+	}
+
+	return nil, false
+}
+
+// DerivesFrom returns whether |rt| has |ancestor| anywhere in its parent chain. The chain can only
+// be followed through indexed types, but |ancestor| itself does not need to be indexed (eg. AActor).
+func (p *Project) DerivesFrom(rt *ReflectedType, ancestor string) bool {
+	visited := map[string]struct{}{}
+	for current := rt; current != nil && current.Parent != ""; {
+		if current.Parent == ancestor {
+			return true
+		}
+
+		// Guard against malformed cycles.
+		if _, ok := visited[current.Parent]; ok {
+			return false
+		}
+		visited[current.Parent] = struct{}{}
+
+		next, ok := p.FindReflectedType(current.Parent)
+		if !ok {
+			return false
+		}
+		current = next
+	}
+
+	return false
+}
+
+// parseReflectedTypes scans the content of a header for UHT macros.
+// Members are attributed to the last type declared before them, following Unreal's conventions.
+func parseReflectedTypes(data []byte) []*ReflectedType {
+	text := string(stripCppComments(data))
+	lines := newLineIndex(data)
+
+	var types []*ReflectedType
+	var current *ReflectedType
+
+	for _, match := range gReflectionMacroRegex.FindAllStringSubmatchIndex(text, -1) {
+		macro := text[match[2]:match[3]]
+
+		// Skip the macro definitions themselves.
+		lineStart := strings.LastIndexByte(text[:match[0]], '\n') + 1
+		if strings.HasPrefix(strings.TrimSpace(text[lineStart:match[0]]), "#") {
+			continue
+		}
+
+		args, end, ok := extractParens(text, match[1]-1)
+		if !ok {
+			continue
+		}
+		specifiers, meta := parseReflectionSpecifiers(args)
+		line := lines.Line(match[0])
+
+		switch macro {
+		case "UFUNCTION", "UPROPERTY":
+			if current == nil {
+				continue
+			}
+
+			member, ok := parseReflectedMember(macro, text[end:])
+			if !ok {
+				continue
+			}
+			member.Specifiers = specifiers
+			member.Meta = meta
+			member.Line = line
+
+			if macro == "UFUNCTION" {
+				current.Functions = append(current.Functions, member)
+			} else {
+				current.Properties = append(current.Properties, member)
+			}
+		default:
+			rt, ok := parseReflectedDeclaration(ReflectedKind(macro), text[end:])
+			if !ok {
+				continue
+			}
+			rt.Specifiers = specifiers
+			rt.Meta = meta
+			rt.Line = line
+
+			types = append(types, rt)
+			if rt.Kind == ReflectedKind_Class || rt.Kind == ReflectedKind_Struct || rt.Kind == ReflectedKind_Interface {
+				current = rt
+			}
+		}
+	}
+
+	return types
+}
+
+// parseReflectedDeclaration parses the declaration that follows a UHT type macro.
+func parseReflectedDeclaration(kind ReflectedKind, text string) (*ReflectedType, bool) {
+	rt := &ReflectedType{
+		Kind: kind,
+	}
+
+	switch kind {
+	case ReflectedKind_Class, ReflectedKind_Struct, ReflectedKind_Interface:
+		decl := collapseWhitespace(cutAtAny(text, "{;"))
+		matches := gReflectedClassRegex.FindStringSubmatch(decl)
+		if matches == nil {
+			return nil, false
+		}
+
+		rt.APIMacro = matches[1]
+		rt.Name = matches[2]
+		for i, base := range splitTopLevel(matches[3], ',') {
+			base = lastBaseName(base)
+			if i == 0 {
+				rt.Parent = base
+			} else {
+				rt.Interfaces = append(rt.Interfaces, base)
+			}
+		}
+	case ReflectedKind_Enum:
+		matches := gReflectedEnumRegex.FindStringSubmatch(strings.TrimSpace(text))
+		if matches == nil {
+			return nil, false
+		}
+		rt.Name = matches[1]
+	case ReflectedKind_Delegate:
+		matches := gReflectedDelegateRegex.FindStringSubmatch(strings.TrimSpace(text))
+		if matches == nil {
+			return nil, false
+		}
+		rt.Name = matches[1]
+	default:
+		return nil, false
+	}
+
+	return rt, true
+}
+
+// parseReflectedMember parses the declaration that follows a UFUNCTION or UPROPERTY macro.
+func parseReflectedMember(macro string, text string) (*ReflectedMember, bool) {
+	var decl string
+	if macro == "UFUNCTION" {
+		// The name is the identifier just before the parameter list.
+		paren := strings.IndexByte(text, '(')
+		if paren < 0 {
+			return nil, false
+		}
+		decl = text[:paren]
+	} else {
+		decl = cutAtAny(text, ";")
+
+		// Remove default initializers, bitfields and array sizes.
+		decl = cutAtAny(decl, "={[")
+		if index := indexSingleColon(decl); index >= 0 {
+			decl = decl[:index]
+		}
+	}
+
+	decl = collapseWhitespace(decl)
+	name := lastIdentifier(decl)
+	if name == "" {
+		return nil, false
+	}
+
+	return &ReflectedMember{
+		Name: name,
+		Type: strings.TrimSpace(strings.TrimSuffix(decl, name)),
+	}, true
+}
+
+// parseReflectionSpecifiers parses the arguments of a UHT macro, splitting the meta specifiers.
+func parseReflectionSpecifiers(args string) ([]string, map[string]string) {
+	var specifiers []string
+	var meta map[string]string
+
+	for _, part := range splitTopLevel(args, ',') {
+		key, value, hasValue := strings.Cut(part, "=")
+		key = strings.TrimSpace(key)
+
+		if hasValue && strings.EqualFold(key, "meta") {
+			value = strings.TrimSpace(value)
+			value = strings.TrimSuffix(strings.TrimPrefix(value, "("), ")")
+
+			if meta == nil {
+				meta = map[string]string{}
+			}
+			for _, entry := range splitTopLevel(value, ',') {
+				metaKey, metaValue, _ := strings.Cut(entry, "=")
+				meta[strings.TrimSpace(metaKey)] = strings.Trim(strings.TrimSpace(metaValue), `"`)
+			}
+			continue
+		}
+
+		specifiers = append(specifiers, collapseWhitespace(part))
+	}
+
+	return specifiers, meta
+}
+
+// lastBaseName strips the access specifiers of a base class declaration.
+func lastBaseName(base string) string {
+	fields := strings.Fields(base)
+	for len(fields) > 1 {
+		switch fields[0] {
+		case "public", "protected", "private", "virtual":
+			fields = fields[1:]
+			continue
+		}
+		break
+	}
+	return strings.Join(fields, " ")
+}
+
+// cutAtAny returns |text| up to the first of any of the |chars|.
+func cutAtAny(text, chars string) string {
+	if index := strings.IndexAny(text, chars); index >= 0 {
+		return text[:index]
+	}
+	return text
+}
+
+// indexSingleColon returns the index of the first ':' that is not part of a '::'.
+func indexSingleColon(text string) int {
+	for i := 0; i < len(text); i++ {
+		if text[i] != ':' {
+			continue
+		}
+
+		if i+1 < len(text) && text[i+1] == ':' {
+			i++
+			continue
+		}
+
+		return i
+	}
+
+	return -1
+}
+
+func collapseWhitespace(text string) string {
+	return gWhitespaceRegex.ReplaceAllString(strings.TrimSpace(text), " ")
+}
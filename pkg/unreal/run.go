@@ -0,0 +1,194 @@
+package unreal
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/cristiandonosoc/golib/pkg/files"
+	"github.com/cristiandonosoc/gunreal/pkg/config"
+)
+
+// LaunchMode is how the project is launched.
+type LaunchMode string
+
+const (
+	LaunchMode_Editor     LaunchMode = "editor"
+	LaunchMode_Game       LaunchMode = "game"
+	LaunchMode_Server     LaunchMode = "server"
+	LaunchMode_Commandlet LaunchMode = "commandlet"
+)
+
+// NewLaunchMode parses the launch mode given by the user.
+func NewLaunchMode(id string) (LaunchMode, error) {
+	switch mode := LaunchMode(strings.ToLower(id)); mode {
+	case LaunchMode_Editor, LaunchMode_Game, LaunchMode_Server, LaunchMode_Commandlet:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unrecognized launch mode %q (expected editor, game, server or commandlet)", id)
+	}
+}
+
+// RunOptions describes how |Run| launches the project.
+type RunOptions struct {
+	Mode LaunchMode
+	// Commandlet is the commandlet to run (-run=<Commandlet>). Only used by LaunchMode_Commandlet.
+	Commandlet string
+	// (optional) Profile adds the map, log, exec cmds and ini overrides it defines.
+	Profile *config.GunrealLaunchProfile
+	// ExtraArgs are appended at the end of the command line.
+	ExtraArgs []string
+}
+
+// EditorExecutable returns the path to the UnrealEditor binary for the host platform. |cmd| selects
+// the console version (UnrealEditor-Cmd), used for servers and commandlets.
+func (p *Project) EditorExecutable(cmd bool) (string, error) {
+	platform, err := HostPlatform()
+	if err != nil {
+		return "", err
+	}
+
+	name := "UnrealEditor"
+	if cmd {
+		name = "UnrealEditor-Cmd"
+	}
+
+	binariesDir := filepath.Join(p.Config.EditorConfig.EditorDir, "Engine", "Binaries", string(platform))
+
+	var path string
+	switch platform {
+	case Platform_Windows:
+		path = filepath.Join(binariesDir, name+".exe")
+	case Platform_Mac:
+		path = filepath.Join(binariesDir, name+".app", "Contents", "MacOS", name)
+	default:
+		path = filepath.Join(binariesDir, name)
+	}
+
+	if _, found, err := files.StatFile(path); err != nil || !found {
+		return "", files.StatFileErrorf(err, "statting editor executable %q", path)
+	}
+
+	return path, nil
+}
+
+// LaunchArgs returns the command line (executable first) |Run| would use with |options|.
+func (p *Project) LaunchArgs(options *RunOptions) ([]string, error) {
+	if p.Config.UProjectPath == "" {
+		return nil, fmt.Errorf("no uproject configured to launch")
+	}
+
+	useCmd := options.Mode == LaunchMode_Server || options.Mode == LaunchMode_Commandlet
+	executable, err := p.EditorExecutable(useCmd)
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{executable, p.Config.UProjectPath}
+
+	profile := options.Profile
+	if profile == nil {
+		profile = &config.GunrealLaunchProfile{}
+	}
+
+	// The map has to come right after the project.
+	if profile.Map != "" {
+		args = append(args, profile.Map)
+	}
+
+	switch options.Mode {
+	case LaunchMode_Game:
+		args = append(args, "-game")
+	case LaunchMode_Server:
+		args = append(args, "-server")
+	case LaunchMode_Commandlet:
+		if options.Commandlet == "" {
+			return nil, fmt.Errorf("no commandlet given")
+		}
+		args = append(args, "-run="+options.Commandlet)
+	}
+
+	if profile.Log {
+		args = append(args, "-log")
+	}
+	if len(profile.ExecCmds) > 0 {
+		args = append(args, "-ExecCmds="+strings.Join(profile.ExecCmds, ","))
+	}
+	for _, ini := range profile.Ini {
+		args = append(args, "-ini:"+ini)
+	}
+	args = append(args, profile.Args...)
+	args = append(args, options.ExtraArgs...)
+
+	return args, nil
+}
+
+// Run launches the project as described by |options| and waits for it to exit. Interrupts received
+// meanwhile are forwarded to the child, so that Unreal gets to shut down cleanly.
+func (p *Project) Run(options *RunOptions) error {
+	args, err := p.LaunchArgs(options)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Dir = p.ProjectDir()
+	setUnrealCommandLine(cmd)
+
+	fmt.Println("> Running:", cmd.Args)
+
+	return runForwardingSignals(cmd)
+}
+
+// runForwardingSignals runs |cmd| passing any termination signal to it instead of letting them kill
+// gunreal, which would leave the child orphaned. Interrupts are only forwarded when they did not
+// already reach the child: Ctrl+C in the terminal (or the console on Windows) is sent to the whole
+// process group, and a repeated one would make Unreal skip its graceful shutdown. A second interrupt
+// is always forwarded, so that the child can be stopped either way. gunreal waits for it to exit.
+func runForwardingSignals(cmd *exec.Cmd) error {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+	defer signal.Stop(signals)
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("starting %v: %w", cmd.Args, err)
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		interrupts := 0
+		for {
+			select {
+			case sig := <-signals:
+				if sig == os.Interrupt {
+					interrupts++
+					if interrupts == 1 && interruptReachesChild() {
+						continue
+					}
+				}
+
+				// Signalling a process is not supported on Windows, so errors are ignored.
+				_ = cmd.Process.Signal(sig)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	if err := cmd.Wait(); err != nil {
+		if exiterr, ok := err.(*exec.ExitError); ok {
+			return fmt.Errorf("%s exited with error code %d", filepath.Base(cmd.Path), exiterr.ExitCode())
+		}
+		return fmt.Errorf("running %v: %w", cmd.Args, err)
+	}
+
+	return nil
+}
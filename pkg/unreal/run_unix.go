@@ -0,0 +1,27 @@
+//go:build !windows
+
+package unreal
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+	"unsafe"
+)
+
+// setUnrealCommandLine is a no-op outside of Windows: arguments are passed as is, and Unreal quotes
+// the values with spaces when it rebuilds its command line.
+func setUnrealCommandLine(cmd *exec.Cmd) {}
+
+// interruptReachesChild returns whether an interrupt got to the child by itself, which is the case
+// when it came from Ctrl+C in a terminal where gunreal (and so the child) is the foreground process
+// group. Otherwise (eg. kill -INT or no terminal at all) gunreal got it alone.
+func interruptReachesChild() bool {
+	var pgrp int32
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, os.Stdin.Fd(), uintptr(syscall.TIOCGPGRP), uintptr(unsafe.Pointer(&pgrp)))
+	if errno != 0 {
+		return false
+	}
+
+	return int(pgrp) == syscall.Getpgrp()
+}
@@ -0,0 +1,36 @@
+package unreal
+
+import (
+	"os/exec"
+	"strings"
+	"syscall"
+)
+
+// setUnrealCommandLine writes the command line of |cmd| the way Unreal parses it: values with spaces
+// are quoted after the '=' (-ExecCmds="stat fps") instead of quoting the whole argument, which is
+// what Go does by default and Unreal does not understand.
+func setUnrealCommandLine(cmd *exec.Cmd) {
+	quoted := make([]string, 0, len(cmd.Args))
+	for i, arg := range cmd.Args {
+		if i == 0 || !strings.ContainsAny(arg, " \t") {
+			quoted = append(quoted, syscall.EscapeArg(arg))
+			continue
+		}
+
+		if strings.HasPrefix(arg, "-") {
+			if index := strings.IndexByte(arg, '='); index >= 0 {
+				quoted = append(quoted, arg[:index+1]+`"`+arg[index+1:]+`"`)
+				continue
+			}
+		}
+		quoted = append(quoted, `"`+arg+`"`)
+	}
+
+	cmd.SysProcAttr = &syscall.SysProcAttr{CmdLine: strings.Join(quoted, " ")}
+}
+
+// interruptReachesChild returns whether an interrupt got to the child by itself. On Windows Ctrl+C
+// is sent to every process attached to the console, so it always does.
+func interruptReachesChild() bool {
+	return true
+}
@@ -0,0 +1,88 @@
+package unreal
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/cristiandonosoc/golib/pkg/files"
+)
+
+var gCppIdentifierRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// validateScaffoldName makes sure |name| can be used as a module, plugin or class name.
+func validateScaffoldName(kind, name string) error {
+	if !gCppIdentifierRegex.MatchString(name) {
+		return fmt.Errorf("%s name %q is not a valid C++ identifier", kind, name)
+	}
+	return nil
+}
+
+// validateScaffoldSubdir checks that |subdir| is a relative path that stays within the directory
+// it is joined to.
+func validateScaffoldSubdir(subdir string) error {
+	slashed := strings.ReplaceAll(subdir, "\\", "/")
+	if filepath.IsAbs(subdir) || filepath.VolumeName(subdir) != "" || strings.HasPrefix(slashed, "/") {
+		return fmt.Errorf("subdir %q must be a relative path", subdir)
+	}
+
+	for _, part := range strings.Split(slashed, "/") {
+		if part == ".." {
+			return fmt.Errorf("subdir %q cannot go up with \"..\"", subdir)
+		}
+	}
+	return nil
+}
+
+// scaffoldFile is a file to be generated from a template.
+type scaffoldFile struct {
+	Path     string
+	Template string
+}
+
+// renderScaffold executes all the templates of |files| with |data|, without writing anything.
+func renderScaffold(files []*scaffoldFile, data any) (map[string][]byte, error) {
+	rendered := map[string][]byte{}
+	for _, file := range files {
+		tmpl, err := template.New(filepath.Base(file.Path)).Parse(file.Template)
+		if err != nil {
+			return nil, fmt.Errorf("parsing template for %q: %w", file.Path, err)
+		}
+
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return nil, fmt.Errorf("rendering template for %q: %w", file.Path, err)
+		}
+		rendered[file.Path] = buf.Bytes()
+	}
+
+	return rendered, nil
+}
+
+// writeScaffold writes the |rendered| files. It never overwrites existing files: the whole scaffold
+// is checked before anything gets written.
+func writeScaffold(rendered map[string][]byte) error {
+	for path := range rendered {
+		if _, found, err := files.StatFile(path); err != nil {
+			return fmt.Errorf("querying %q: %w", path, err)
+		} else if found {
+			return fmt.Errorf("%q already exists", path)
+		}
+	}
+
+	for path, data := range rendered {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("creating dir for %q: %w", path, err)
+		}
+
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return fmt.Errorf("writing %q: %w", path, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,199 @@
+package unreal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// SymbolKind is the kind of declaration found by the symbol scanner.
+type SymbolKind string
+
+const (
+	SymbolKind_Class    SymbolKind = "class"
+	SymbolKind_Struct   SymbolKind = "struct"
+	SymbolKind_Enum     SymbolKind = "enum"
+	SymbolKind_Function SymbolKind = "function"
+	SymbolKind_Macro    SymbolKind = "macro"
+)
+
+// NewSymbolKind attempts to unify the symbol kind from identifiers that might come from the outside.
+func NewSymbolKind(id string) (SymbolKind, error) {
+	switch strings.ToLower(id) {
+	case "class":
+		return SymbolKind_Class, nil
+	case "struct":
+		return SymbolKind_Struct, nil
+	case "enum":
+		return SymbolKind_Enum, nil
+	case "function", "func":
+		return SymbolKind_Function, nil
+	case "macro", "define":
+		return SymbolKind_Macro, nil
+	default:
+		return "", fmt.Errorf("unrecognized symbol kind %q", id)
+	}
+}
+
+// Symbol is a definition found within a module file.
+type Symbol struct {
+	Name   string
+	Kind   SymbolKind
+	Module *Module
+	File   string
+	Line   int
+}
+
+func (s *Symbol) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&struct {
+		Name   string     `json:"name"`
+		Kind   SymbolKind `json:"kind"`
+		Module string     `json:"module"`
+		File   string     `json:"file"`
+		Line   int        `json:"line"`
+	}{
+		Name:   s.Name,
+		Kind:   s.Kind,
+		Module: s.Module.Name,
+		File:   s.File,
+		Line:   s.Line,
+	})
+}
+
+// gSymbolSourceExtensions are the (lowercase) extensions of the files the symbol scanner reads.
+var gSymbolSourceExtensions = []string{
+	".h",
+	".hpp",
+	".inl",
+	".cpp",
+	".c",
+	".cc",
+}
+
+var (
+	gSymbolMacroRegex = regexp.MustCompile(`(?m)^[ \t]*#[ \t]*define[ \t]+(\w+)`)
+
+	// Only definitions (with a body) are matched, so forward declarations are skipped.
+	gSymbolRecordRegex = regexp.MustCompile(`(?m)^[ \t]*(?:template\s*<[^;{}]*>\s*)?(class|struct)\s+(?:\w+_API\s+)?(\w+)(?:\s+final)?\s*(?::[^;{}()]*)?\{`)
+	gSymbolEnumRegex   = regexp.MustCompile(`(?m)^[ \t]*enum\s+(?:class\s+|struct\s+)?(\w+)\s*(?::\s*\w+\s*)?\{`)
+
+	// Parameter lists support one level of nested parenthesis (eg. default arguments).
+	gSymbolFunctionRegex = regexp.MustCompile(`(?m)^[ \t]*(?:[\w:<>,*&~ \t]*?[\s*&])?((?:\w+::)*~?\w+)[ \t]*\((?:[^;{}()]|\([^;{}()]*\))*\)(?:\s*(?:const|override|final|noexcept|&&|&))*\s*(?::[^;{}]*)?\{`)
+
+	// Keywords that look like function definitions to the regex above.
+	gSymbolFunctionKeywords = map[string]struct{}{
+		"if":     {},
+		"for":    {},
+		"while":  {},
+		"switch": {},
+		"catch":  {},
+		"return": {},
+		"sizeof": {},
+	}
+)
+
+// FindSymbols scans all the source files of the indexed modules for definitions whose name matches
+// |pattern|. If |kinds| is non-empty, only those kinds of symbols are returned.
+// This does not require compiling: it's a lightweight scanner that follows common conventions.
+func (p *Project) FindSymbols(ctx context.Context, pattern *regexp.Regexp, kinds []SymbolKind) ([]*Symbol, error) {
+	if !p.IsIndexed() {
+		return nil, fmt.Errorf("no modules loaded. Is the project indexed?")
+	}
+
+	files := p.moduleFiles(func(path string) bool {
+		return hasAnySuffix(strings.ToLower(path), gSymbolSourceExtensions)
+	})
+
+	symbols, err := scanModuleFiles(ctx, files, func(mf *ModuleFile) ([]*Symbol, error) {
+		data, err := os.ReadFile(mf.Path)
+		if err != nil {
+			return nil, fmt.Errorf("reading %q: %w", mf.Path, err)
+		}
+
+		var result []*Symbol
+		for _, symbol := range scanSymbols(data) {
+			if len(kinds) > 0 && !slices.Contains(kinds, symbol.Kind) {
+				continue
+			}
+
+			if !pattern.MatchString(symbol.Name) {
+				continue
+			}
+
+			symbol.Module = mf.Module
+			symbol.File = mf.Path
+			result = append(result, symbol)
+		}
+
+		return result, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scanning files: %w", err)
+	}
+
+	sort.Slice(symbols, func(i, j int) bool {
+		if symbols[i].Name != symbols[j].Name {
+			return symbols[i].Name < symbols[j].Name
+		}
+		if symbols[i].File != symbols[j].File {
+			return symbols[i].File < symbols[j].File
+		}
+		return symbols[i].Line < symbols[j].Line
+	})
+
+	return symbols, nil
+}
+
+// scanSymbols finds the definitions within the content of a C++ file.
+// The returned symbols only have their name, kind and line set.
+func scanSymbols(data []byte) []*Symbol {
+	text := string(stripCppComments(data))
+	lines := newLineIndex(data)
+
+	var symbols []*Symbol
+	add := func(name string, kind SymbolKind, offset int) {
+		symbols = append(symbols, &Symbol{
+			Name: name,
+			Kind: kind,
+			Line: lines.Line(offset),
+		})
+	}
+
+	for _, match := range gSymbolMacroRegex.FindAllStringSubmatchIndex(text, -1) {
+		add(text[match[2]:match[3]], SymbolKind_Macro, match[2])
+	}
+
+	for _, match := range gSymbolRecordRegex.FindAllStringSubmatchIndex(text, -1) {
+		kind := SymbolKind_Class
+		if text[match[2]:match[3]] == "struct" {
+			kind = SymbolKind_Struct
+		}
+		add(text[match[4]:match[5]], kind, match[4])
+	}
+
+	for _, match := range gSymbolEnumRegex.FindAllStringSubmatchIndex(text, -1) {
+		add(text[match[2]:match[3]], SymbolKind_Enum, match[2])
+	}
+
+	for _, match := range gSymbolFunctionRegex.FindAllStringSubmatchIndex(text, -1) {
+		name := text[match[2]:match[3]]
+		if _, ok := gSymbolFunctionKeywords[name]; ok {
+			continue
+		}
+
+		// Preprocessor lines are not function definitions.
+		lineStart := strings.LastIndexByte(text[:match[2]], '\n') + 1
+		if strings.HasPrefix(strings.TrimSpace(text[lineStart:match[2]]), "#") {
+			continue
+		}
+
+		add(name, SymbolKind_Function, match[2])
+	}
+
+	return symbols
+}
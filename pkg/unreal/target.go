@@ -0,0 +1,273 @@
+package unreal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+const (
+	UnrealTargetFileSuffix = ".Target.cs"
+)
+
+// TargetType mirrors the UBT TargetType enum.
+type TargetType string
+
+const (
+	TargetType_Game    TargetType = "Game"
+	TargetType_Editor  TargetType = "Editor"
+	TargetType_Client  TargetType = "Client"
+	TargetType_Server  TargetType = "Server"
+	TargetType_Program TargetType = "Program"
+)
+
+// NewTargetType parses a target type, as written in a .Target.cs file or given by the user.
+func NewTargetType(id string) (TargetType, error) {
+	for _, tt := range []TargetType{TargetType_Game, TargetType_Editor, TargetType_Client, TargetType_Server, TargetType_Program} {
+		if strings.EqualFold(id, string(tt)) {
+			return tt, nil
+		}
+	}
+
+	return "", fmt.Errorf("unrecognized target type %q", id)
+}
+
+// AllowsModuleType returns whether a module whose descriptor has |moduleType| (eg. "Runtime",
+// "Editor") gets built into targets of this type. This follows the rules of UBT's
+// ModuleHostType, with developer modules treated as editor only.
+func (tt TargetType) AllowsModuleType(moduleType string) bool {
+	switch strings.ToLower(moduleType) {
+	case "editor", "editornocommandlet", "uncookedonly", "developer", "developertool":
+		return tt == TargetType_Editor
+	case "editorandprogram":
+		return tt == TargetType_Editor || tt == TargetType_Program
+	case "cookedonly":
+		return tt != TargetType_Editor && tt != TargetType_Program
+	case "serveronly":
+		return tt != TargetType_Client && tt != TargetType_Program
+	case "clientonly", "clientonlynocommandlet":
+		return tt != TargetType_Server && tt != TargetType_Program
+	case "program":
+		return tt == TargetType_Program
+	case "runtimeandprogram":
+		return true
+	default:
+		// Runtime, RuntimeNoCommandlet and anything we don't know about.
+		return tt != TargetType_Program
+	}
+}
+
+// Target represents a .Target.cs file of the project.
+type Target struct {
+	Name string
+	Type TargetType
+	Path string
+	// ExtraModuleNames are the modules the target explicitly asks for.
+	ExtraModuleNames []string
+}
+
+func (t *Target) String() string {
+	return fmt.Sprintf("%s (%s)", t.Name, t.Type)
+}
+
+var (
+	gTargetTypeRegex        = regexp.MustCompile(`\bType\s*=\s*TargetType\s*\.\s*(\w+)`)
+	gTargetExtraModuleRegex = regexp.MustCompile(`\bExtraModuleNames\s*\.\s*(?:AddRange|Add)\s*\(`)
+)
+
+// findTargets returns the targets defined at the root of |sourceDir|, which is where UBT expects
+// them to be.
+func findTargets(sourceDir string) (map[string]*Target, error) {
+	entries, err := os.ReadDir(sourceDir)
+	if err != nil {
+		return nil, fmt.Errorf("reading %q: %w", sourceDir, err)
+	}
+
+	targets := map[string]*Target{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), UnrealTargetFileSuffix) {
+			continue
+		}
+
+		target, err := parseTargetRules(filepath.Join(sourceDir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		targets[target.Name] = target
+	}
+
+	return targets, nil
+}
+
+// parseTargetRules reads the target type and the extra modules out of the target file at |path|.
+// Like |parseModuleRules|, no C# logic is evaluated.
+func parseTargetRules(path string) (*Target, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %q: %w", path, err)
+	}
+
+	text := string(stripCppComments(data))
+
+	target := &Target{
+		Name: strings.TrimSuffix(filepath.Base(path), UnrealTargetFileSuffix),
+		// UBT defaults to game targets.
+		Type: TargetType_Game,
+		Path: path,
+	}
+
+	if match := gTargetTypeRegex.FindStringSubmatch(text); match != nil {
+		tt, err := NewTargetType(match[1])
+		if err != nil {
+			return nil, fmt.Errorf("parsing %q: %w", path, err)
+		}
+		target.Type = tt
+	}
+
+	for _, match := range gTargetExtraModuleRegex.FindAllStringIndex(text, -1) {
+		args, _, ok := extractParens(text, match[1]-1)
+		if !ok {
+			continue
+		}
+
+		for _, str := range gCSharpStringRegex.FindAllStringSubmatch(args, -1) {
+			target.ExtraModuleNames = append(target.ExtraModuleNames, str[1])
+		}
+	}
+	target.ExtraModuleNames = sortedUnique(target.ExtraModuleNames)
+
+	return target, nil
+}
+
+// moduleDescriptor returns the .uproject or .uplugin entry that declares |module|, if any.
+func (p *Project) moduleDescriptor(module *Module) *UProjectModule {
+	var descriptors []*UProjectModule
+	if module.Plugin != nil {
+		if module.Plugin.Descriptor != nil {
+			descriptors = module.Plugin.Descriptor.Modules
+		}
+	} else if p.LoadedUProject != nil {
+		descriptors = p.LoadedUProject.Modules
+	}
+
+	for _, descriptor := range descriptors {
+		if descriptor.Name == module.Name {
+			return descriptor
+		}
+	}
+
+	return nil
+}
+
+// moduleAllowedInTarget returns whether the descriptor of |module| lets it be built for |target|.
+// Modules without a descriptor are only built when something depends on them, so they are allowed.
+func (p *Project) moduleAllowedInTarget(module *Module, target *Target) bool {
+	if module.Plugin != nil && !p.pluginAllowedInTarget(module.Plugin, target) {
+		return false
+	}
+
+	descriptor := p.moduleDescriptor(module)
+	if descriptor == nil {
+		return true
+	}
+
+	if !target.Type.AllowsModuleType(descriptor.Type) {
+		return false
+	}
+	if len(descriptor.TargetAllowList) > 0 && !slices.Contains(descriptor.TargetAllowList, string(target.Type)) {
+		return false
+	}
+	if slices.Contains(descriptor.TargetDenyList, string(target.Type)) {
+		return false
+	}
+
+	return true
+}
+
+// pluginAllowedInTarget checks the reference to |plugin| within the .uproject. Project plugins are
+// enabled unless the .uproject (or the plugin itself) says otherwise.
+func (p *Project) pluginAllowedInTarget(plugin *Plugin, target *Target) bool {
+	enabled := true
+	if plugin.Descriptor != nil && plugin.Descriptor.EnabledByDefault != nil {
+		enabled = *plugin.Descriptor.EnabledByDefault
+	}
+
+	if p.LoadedUProject == nil {
+		return enabled
+	}
+
+	for _, ref := range p.LoadedUProject.Plugins {
+		if ref.Name != plugin.Name {
+			continue
+		}
+
+		if !ref.Enabled {
+			return false
+		}
+		if len(ref.TargetAllowList) > 0 && !slices.Contains(ref.TargetAllowList, string(target.Type)) {
+			return false
+		}
+		return true
+	}
+
+	return enabled
+}
+
+// TargetModules returns the names of the indexed modules that get built as part of |target|,
+// sorted. These are the modules the target asks for, the declared modules that are allowed for the
+// target type and everything they depend on.
+// Because dependencies are parsed without evaluating the build files, dependencies on modules that
+// are not allowed in the target (eg. an editor module added under a bBuildEditor condition) are
+// ignored.
+func (p *Project) TargetModules(target *Target) []string {
+	var queue []string
+	queue = append(queue, target.ExtraModuleNames...)
+	for _, module := range p.sortedModules() {
+		if p.moduleDescriptor(module) != nil && p.moduleAllowedInTarget(module, target) {
+			queue = append(queue, module.Name)
+		}
+	}
+
+	visited := map[string]struct{}{}
+	var result []string
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+
+		if _, ok := visited[name]; ok {
+			continue
+		}
+		visited[name] = struct{}{}
+
+		module, ok := p.Modules[name]
+		if !ok || !p.moduleAllowedInTarget(module, target) {
+			continue
+		}
+
+		result = append(result, name)
+		if module.Rules != nil {
+			queue = append(queue, module.Rules.AllDependencies()...)
+			queue = append(queue, module.Rules.DynamicallyLoaded...)
+		}
+	}
+
+	sort.Strings(result)
+	return result
+}
+
+// sortedTargets returns the targets sorted by name.
+func (p *Project) sortedTargets() []*Target {
+	targets := make([]*Target, 0, len(p.Targets))
+	for _, target := range p.Targets {
+		targets = append(targets, target)
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i].Name < targets[j].Name
+	})
+
+	return targets
+}
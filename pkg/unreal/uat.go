@@ -0,0 +1,50 @@
+package unreal
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// UAT runs the AutomationTool (what RunUAT.bat calls) with |args|, through the dotnet the engine
+// resolved. Interrupts are forwarded to it, so that a cancelled BuildCookRun can clean up.
+func (p *Project) UAT(args []string) error {
+	return p.UATWithOutput(args, os.Stdout)
+}
+
+// UATWithOutput runs UAT like |UAT|, but everything it outputs (stdout and stderr) goes to |output|,
+// so that callers can parse it.
+func (p *Project) UATWithOutput(args []string, output io.Writer) error {
+	cmd, err := uatCmd(p, args)
+	if err != nil {
+		return err
+	}
+	cmd.Stdout = output
+	cmd.Stderr = output
+
+	fmt.Fprintln(output, "> Running:", cmd.Args)
+
+	if err := runForwardingSignals(cmd); err != nil {
+		return fmt.Errorf("running UAT: %w", err)
+	}
+
+	return nil
+}
+
+func uatCmd(p *Project, args []string) (*exec.Cmd, error) {
+	editor := p.Config.EditorConfig
+	if editor.UATDll == "" {
+		return nil, fmt.Errorf("AutomationTool not found in %q. Run RunUAT once to build it", editor.EditorDir)
+	}
+
+	var cmdargs []string
+	cmdargs = append(cmdargs, editor.UATDll)
+	cmdargs = append(cmdargs, args...)
+
+	cmd := exec.Command(editor.Dotnet, cmdargs...)
+	cmd.Dir = filepath.Join(editor.EditorDir, "Engine", "Source")
+
+	return cmd, nil
+}
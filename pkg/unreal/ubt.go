@@ -2,6 +2,7 @@ package unreal
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -13,6 +14,22 @@ func (p *Project) UBT(args []string) error {
 
 	fmt.Println("> Running:", cmd.Args)
 
+	return runUBTCmd(cmd)
+}
+
+// UBTWithOutput runs UBT like |UBT|, but everything it outputs (stdout and stderr) goes to |output|
+// instead of the terminal, so that callers can parse it.
+func (p *Project) UBTWithOutput(args []string, output io.Writer) error {
+	cmd := experimentalDirectCmd(p, args)
+	cmd.Stdout = output
+	cmd.Stderr = output
+
+	fmt.Fprintln(output, "> Running:", cmd.Args)
+
+	return runUBTCmd(cmd)
+}
+
+func runUBTCmd(cmd *exec.Cmd) error {
 	if err := cmd.Start(); err != nil {
 		return fmt.Errorf("starting %v: %w", cmd.Args, err)
 	}
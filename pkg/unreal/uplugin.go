@@ -0,0 +1,45 @@
+package unreal
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// UPlugin is the content of a .uplugin descriptor. Modules and plugin references share their format
+// with the .uproject ones.
+type UPlugin struct {
+	FileVersion           int    `json:"FileVersion"`
+	Version               int    `json:"Version"`
+	VersionName           string `json:"VersionName"`
+	FriendlyName          string `json:"FriendlyName"`
+	Description           string `json:"Description"`
+	Category              string `json:"Category"`
+	CreatedBy             string `json:"CreatedBy"`
+	CreatedByURL          string `json:"CreatedByURL"`
+	DocsURL               string `json:"DocsURL"`
+	MarketplaceURL        string `json:"MarketplaceURL"`
+	SupportURL            string `json:"SupportURL"`
+	EnabledByDefault      *bool  `json:"EnabledByDefault,omitempty"`
+	CanContainContent     bool   `json:"CanContainContent"`
+	IsBetaVersion         bool   `json:"IsBetaVersion"`
+	IsExperimentalVersion bool   `json:"IsExperimentalVersion"`
+	Installed             bool   `json:"Installed"`
+
+	Modules []*UProjectModule `json:"Modules,omitempty"`
+	Plugins []*UProjectPlugin `json:"Plugins,omitempty"`
+}
+
+func loadUPluginFile(path string) (*UPlugin, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %q: %w", path, err)
+	}
+
+	uplugin := &UPlugin{}
+	if err := json.Unmarshal(data, uplugin); err != nil {
+		return nil, fmt.Errorf("unmarshalling uplugin: %w", err)
+	}
+
+	return uplugin, nil
+}
@@ -10,14 +10,16 @@ type UProjectModule struct {
 	Name                   string   `json:"Name"`
 	Type                   string   `json:"Type"`
 	LoadingPhase           string   `json:"LoadingPhase"`
-	AdditionalDependencies []string `json:"AdditionalDependencies"`
+	AdditionalDependencies []string `json:"AdditionalDependencies,omitempty"`
+	TargetAllowList        []string `json:"TargetAllowList,omitempty"`
+	TargetDenyList         []string `json:"TargetDenyList,omitempty"`
 }
 
 type UProjectPlugin struct {
 	Name            string   `json:"Name"`
 	Enabled         bool     `json:"Enabled"`
-	TargetAllowList []string `json:"TargetAllowList"`
-	WorkspaceURL    string   `json:"WorkspaceURL"`
+	TargetAllowList []string `json:"TargetAllowList,omitempty"`
+	WorkspaceURL    string   `json:"WorkspaceURL,omitempty"`
 }
 
 type UProject struct {
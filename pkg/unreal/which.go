@@ -0,0 +1,117 @@
+package unreal
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/cristiandonosoc/golib/pkg/files"
+)
+
+// WhichResult describes where a module, file or include comes from.
+type WhichResult struct {
+	Query string `json:"query"`
+	// File is the file the query resolved to. Empty for module queries.
+	File      string `json:"file,omitempty"`
+	Module    string `json:"module"`
+	Plugin    string `json:"plugin,omitempty"`
+	Engine    bool   `json:"engine"`
+	BaseDir   string `json:"base_dir"`
+	BuildFile string `json:"build_file"`
+}
+
+func (wr *WhichResult) String() string {
+	var sb strings.Builder
+	if wr.File != "" {
+		sb.WriteString(fmt.Sprintf("%s\n", wr.File))
+	}
+
+	origin := "project"
+	if wr.Engine {
+		origin = "engine"
+	}
+	sb.WriteString(fmt.Sprintf("- MODULE: %s (%s)\n", wr.Module, origin))
+	if wr.Plugin != "" {
+		sb.WriteString(fmt.Sprintf("- PLUGIN: %s\n", wr.Plugin))
+	}
+	sb.WriteString(fmt.Sprintf("- BASE DIR: %s\n", wr.BaseDir))
+	sb.WriteString(fmt.Sprintf("- BUILD FILE: %s", wr.BuildFile))
+
+	return sb.String()
+}
+
+// Which answers where |query| comes from. The query can be a module name, a path to a file or an
+// include path as it would be written in an #include (eg. "GameFramework/Actor.h").
+// Engine modules are only known if the engine is indexed.
+func (p *Project) Which(query string) (*WhichResult, error) {
+	if module, ok := p.LookupModule(query); ok {
+		return p.newWhichResult(query, "", module), nil
+	}
+
+	if abs, err := filepath.Abs(query); err == nil {
+		if _, found, err := files.StatFile(abs); err == nil && found {
+			if module := p.owningModule(abs); module != nil {
+				return p.newWhichResult(query, abs, module), nil
+			}
+			return nil, fmt.Errorf("file %q does not belong to any known module", abs)
+		}
+	}
+
+	include := filepath.FromSlash(query)
+	for _, module := range p.sortedModules() {
+		for _, dir := range []string{module.PublicDir(), module.ClassesDir()} {
+			candidate := filepath.Join(dir, include)
+			if _, found := slices.BinarySearch(module.Files, candidate); found {
+				return p.newWhichResult(query, candidate, module), nil
+			}
+		}
+	}
+
+	if p.Engine != nil {
+		if header, ok := p.Engine.FindPublicHeader(include); ok {
+			return p.newWhichResult(query, header, p.owningModule(header)), nil
+		}
+	}
+
+	return nil, fmt.Errorf("%q is not a known module, file or include", query)
+}
+
+// owningModule returns the project or engine module that holds |path|. Nil if none does.
+func (p *Project) owningModule(path string) *Module {
+	if module, err := p.identifyModule(path); err == nil {
+		return module
+	}
+
+	if p.Engine == nil {
+		return nil
+	}
+
+	var candidate *Module
+	for _, module := range p.Engine.Modules {
+		if !strings.HasPrefix(path, module.BaseDir+string(filepath.Separator)) {
+			continue
+		}
+		if candidate == nil || len(module.BaseDir) > len(candidate.BaseDir) {
+			candidate = module
+		}
+	}
+
+	return candidate
+}
+
+func (p *Project) newWhichResult(query, file string, module *Module) *WhichResult {
+	result := &WhichResult{
+		Query:     query,
+		File:      file,
+		Module:    module.Name,
+		Engine:    p.IsEngineModule(module),
+		BaseDir:   module.BaseDir,
+		BuildFile: module.BuildFile,
+	}
+	if module.Plugin != nil {
+		result.Plugin = module.Plugin.Name
+	}
+
+	return result
+}